@@ -37,3 +37,31 @@ func CACHE_JSON_EVENT_TYPE_CHART(userID uuid.UUID, projectID uuid.UUID) string {
 func CACHE_JSON_EVENT_LABEL_CHART(userID uuid.UUID, projectID uuid.UUID) string {
 	return fmt.Sprintf("cache:json-event-label-chart/%s/%s", userID, projectID)
 }
+
+func CACHE_EVENT_ID(projectID uuid.UUID, eventID string) string {
+	return fmt.Sprintf("cache:event-id/%s/%s", projectID, eventID)
+}
+
+func CACHE_SIGNATURE(projectID uuid.UUID, signature string) string {
+	return fmt.Sprintf("cache:signature/%s/%s", projectID, signature)
+}
+
+func CACHE_VISITOR_SALT(day string) string {
+	return fmt.Sprintf("cache:visitor-salt/%s", day)
+}
+
+func CACHE_RATE_LIMIT_KEY(userID uuid.UUID) string {
+	return fmt.Sprintf("cache:rate-limit/key/%s", userID)
+}
+
+func CACHE_RATE_LIMIT_PROJECT(projectID uuid.UUID) string {
+	return fmt.Sprintf("cache:rate-limit/project/%s", projectID)
+}
+
+func CACHE_MONTHLY_EVENTS(userID uuid.UUID, month string) string {
+	return fmt.Sprintf("cache:monthly-events/%s/%s", userID, month)
+}
+
+func CACHE_PROJECT_MONTHLY_EVENTS(projectID uuid.UUID, month string) string {
+	return fmt.Sprintf("cache:project-monthly-events/%s/%s", projectID, month)
+}
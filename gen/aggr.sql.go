@@ -14,21 +14,21 @@ import (
 
 const getBriefAggr = `-- name: GetBriefAggr :one
 SELECT 
-COUNT(e.id) AS total_events,
-COUNT(DISTINCT e.ip_addr) AS total_unique_users,
+COALESCE(ROUND(SUM(1 / e.sample_rate)), 0)::bigint AS total_events,
+COUNT(DISTINCT COALESCE(e.distinct_id, e.anonymous_id, e.visitor_hash, e.ip_addr::text)) AS total_unique_users,
 COUNT(DISTINCT e.event_type) AS total_event_type,
 COUNT(DISTINCT e.country) AS total_country_visited,
 (
-    SELECT sub.page_url FROM events AS sub
-    WHERE sub.user_id = $2 AND sub.project_id = $1
-    GROUP BY sub.page_url ORDER BY COUNT(sub.page_url) DESC LIMIT 1
+    SELECT COALESCE(sub.page_path, sub.page_url) FROM events AS sub
+    WHERE sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY COALESCE(sub.page_path, sub.page_url) ORDER BY SUM(1 / sub.sample_rate) DESC LIMIT 1
 ) AS most_visited_url,
 (
     SELECT sub.country FROM events AS sub
-    WHERE sub.user_id = $2 AND sub.project_id = $1
-    GROUP BY sub.country ORDER BY COUNT(sub.country) DESC LIMIT 1
+    WHERE sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY sub.country ORDER BY SUM(1 / sub.sample_rate) FILTER (WHERE sub.country IS NOT NULL) DESC NULLS LAST LIMIT 1
 ) AS most_country_visited
-FROM events AS e WHERE e.user_id = $2 AND e.project_id = $1
+FROM events AS e WHERE e.user_id = $2 AND e.project_id = $1 AND e.is_bot = FALSE
 `
 
 type GetBriefAggrParams struct {
@@ -62,88 +62,138 @@ func (q *Queries) GetBriefAggr(ctx context.Context, arg GetBriefAggrParams) (Get
 const getDetailAggr = `-- name: GetDetailAggr :many
 WITH 
 most_visited_url AS (
-    SELECT 'most_visited_url' AS query_type, sub.page_url AS name, COUNT(sub.page_url) AS total
+    SELECT 'most_visited_url' AS query_type, COALESCE(sub.page_path, sub.page_url) AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
-    WHERE sub.page_url IS NOT NULL AND sub.page_url <> ''
-    AND sub.user_id = $2 AND sub.project_id = $1
-    GROUP BY sub.page_url 
-    ORDER BY COUNT(*) DESC 
+    WHERE COALESCE(sub.page_path, sub.page_url) IS NOT NULL AND COALESCE(sub.page_path, sub.page_url) <> ''
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY COALESCE(sub.page_path, sub.page_url) 
+    ORDER BY total DESC 
+    LIMIT 5
+),
+most_visited_source AS (
+    SELECT 'most_visited_source' AS query_type, COALESCE(sub.utm_source, sub.referrer_domain, sub.channel) AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
+    FROM events sub
+    WHERE COALESCE(sub.utm_source, sub.referrer_domain, sub.channel) IS NOT NULL
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY COALESCE(sub.utm_source, sub.referrer_domain, sub.channel) 
+    ORDER BY total DESC 
+    LIMIT 5
+),
+most_visited_campaign AS (
+    SELECT 'most_visited_campaign' AS query_type, sub.utm_campaign AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
+    FROM events sub
+    WHERE sub.utm_campaign IS NOT NULL AND sub.utm_campaign <> ''
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY sub.utm_campaign 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 most_visited_country AS (
-    SELECT 'most_visited_country' AS query_type, sub.country AS name, COUNT(sub.country) AS total
+    SELECT 'most_visited_country' AS query_type, sub.country AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.country IS NOT NULL AND sub.country <> ''
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.country 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 most_visited_city AS (
-    SELECT 'most_visited_city' AS query_type, sub.city AS name, COUNT(sub.city) AS total
+    SELECT 'most_visited_city' AS query_type, sub.city AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.city IS NOT NULL AND sub.city <> '' 
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.city 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 most_hit_element AS (
-    SELECT 'most_hit_element' AS query_type, sub.element_path AS name, COUNT(sub.element_path) AS total
+    SELECT 'most_hit_element' AS query_type, sub.element_path AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.element_path IS NOT NULL AND sub.element_path <> '' 
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.element_path 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 last_visited_user AS (
     SELECT 'last_visited_user' AS query_type, 
-           sub.ip_addr::text AS name, 
+           COALESCE(sub.distinct_id, sub.ip_addr::text, LEFT(sub.visitor_hash, 16)) AS name, 
            sub.received_at AS timestamp
     FROM events sub
-    WHERE sub.ip_addr IS NOT NULL
-    AND sub.user_id = $2 AND sub.project_id = $1
+    WHERE (sub.distinct_id IS NOT NULL OR sub.ip_addr IS NOT NULL OR sub.visitor_hash IS NOT NULL)
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     ORDER BY sub.received_at DESC 
     LIMIT 5
 ),
 most_used_browser AS (
-    SELECT 'most_used_browser' AS query_type, sub.browser_name AS name, COUNT(sub.browser_name) AS total
+    SELECT 'most_used_browser' AS query_type, sub.browser_name AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.browser_name IS NOT NULL AND sub.browser_name <> ''
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.browser_name 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
+    LIMIT 5
+),
+most_used_os AS (
+    SELECT 'most_used_os' AS query_type, sub.os_name AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
+    FROM events sub
+    WHERE sub.os_name IS NOT NULL AND sub.os_name <> ''
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY sub.os_name 
+    ORDER BY total DESC 
+    LIMIT 5
+),
+most_used_device AS (
+    SELECT 'most_used_device' AS query_type, sub.device_type AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
+    FROM events sub
+    WHERE sub.device_type IS NOT NULL AND sub.device_type <> ''
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY sub.device_type 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 most_event_type AS (
-    SELECT 'most_event_type' AS query_type, sub.event_type AS name, COUNT(sub.event_type) AS total
+    SELECT 'most_event_type' AS query_type, sub.event_type AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.event_type IS NOT NULL AND sub.event_type <> ''
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.event_type 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
     LIMIT 5
 ),
 most_event_label AS (
-    SELECT 'most_event_label' AS query_type, sub.event_label AS name, COUNT(sub.event_label) AS total
+    SELECT 'most_event_label' AS query_type, sub.event_label AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
     FROM events sub
     WHERE sub.event_label IS NOT NULL AND sub.event_label <> ''
-    AND sub.user_id = $2 AND sub.project_id = $1
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
     GROUP BY sub.event_label 
-    ORDER BY COUNT(*) DESC 
+    ORDER BY total DESC 
+    LIMIT 5
+),
+most_used_property AS (
+    SELECT 'most_used_property' AS query_type, prop.key AS name, ROUND(SUM(1 / sub.sample_rate))::bigint AS total
+    FROM events sub, jsonb_object_keys(sub.properties) AS prop(key)
+    WHERE sub.properties IS NOT NULL
+    AND sub.user_id = $2 AND sub.project_id = $1 AND sub.is_bot = FALSE
+    GROUP BY prop.key 
+    ORDER BY total DESC 
     LIMIT 5
 )
 
 SELECT query_type, name, CAST(total AS text) AS total -- Why cast total as text? so it can be used to also hold the timestamp
 FROM (
     SELECT query_type, name, total FROM most_visited_url
+    UNION ALL SELECT query_type, name, total FROM most_visited_source
+    UNION ALL SELECT query_type, name, total FROM most_visited_campaign
     UNION ALL SELECT query_type, name, total FROM most_visited_country
     UNION ALL SELECT query_type, name, total FROM most_visited_city
     UNION ALL SELECT query_type, name, total FROM most_hit_element
     UNION ALL SELECT query_type, name, total FROM most_used_browser
+    UNION ALL SELECT query_type, name, total FROM most_used_os
+    UNION ALL SELECT query_type, name, total FROM most_used_device
     UNION ALL SELECT query_type, name, total FROM most_event_type
     UNION ALL SELECT query_type, name, total FROM most_event_label
+    UNION ALL SELECT query_type, name, total FROM most_used_property
 ) count_queries
 UNION ALL
 
@@ -184,12 +234,12 @@ func (q *Queries) GetDetailAggr(ctx context.Context, arg GetDetailAggrParams) ([
 
 const getTotalAggr = `-- name: GetTotalAggr :one
 SELECT 
-    COUNT(id) AS total_events,
+    COALESCE(ROUND(SUM(1 / sample_rate)), 0)::bigint AS total_events,
     COUNT(DISTINCT event_type) AS total_event_type,
-    COUNT(DISTINCT ip_addr) AS total_unique_users,
+    COUNT(DISTINCT COALESCE(distinct_id, anonymous_id, visitor_hash, ip_addr::text)) AS total_unique_users,
     COUNT(DISTINCT country) AS total_country_visited,
-    COUNT(DISTINCT page_url) AS total_page_url
-FROM events WHERE user_id = $2 AND project_id = $1
+    COUNT(DISTINCT COALESCE(page_path, page_url)) AS total_page_url
+FROM events WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE
 `
 
 type GetTotalAggrParams struct {
@@ -7,6 +7,7 @@ package gen
 
 import (
 	"context"
+	"net/netip"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -14,45 +15,89 @@ import (
 
 const createAPIKey = `-- name: CreateAPIKey :one
 INSERT INTO 
-    api_keys(name, token, user_id, created_at, expired_at) VALUES ($1, $2, $3, $4, $5) 
-RETURNING name, token, created_at, expired_at
+    api_keys(name, token_prefix, token_salt, token_hash, user_id, created_at, expired_at, scopes, project_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+RETURNING name, token_prefix, created_at, expired_at, scopes, project_id
 `
 
 type CreateAPIKeyParams struct {
-	Name      string
-	Token     string
-	UserID    uuid.UUID
-	CreatedAt pgtype.Timestamptz
-	ExpiredAt pgtype.Timestamptz
+	Name        string
+	TokenPrefix string
+	TokenSalt   string
+	TokenHash   string
+	UserID      uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+	ExpiredAt   pgtype.Timestamptz
+	Scopes      []string
+	ProjectID   pgtype.UUID
 }
 
 type CreateAPIKeyRow struct {
-	Name      string
-	Token     string
-	CreatedAt pgtype.Timestamptz
-	ExpiredAt pgtype.Timestamptz
+	Name        string
+	TokenPrefix string
+	CreatedAt   pgtype.Timestamptz
+	ExpiredAt   pgtype.Timestamptz
+	Scopes      []string
+	ProjectID   pgtype.UUID
 }
 
 func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (CreateAPIKeyRow, error) {
 	row := q.db.QueryRow(ctx, createAPIKey,
 		arg.Name,
-		arg.Token,
+		arg.TokenPrefix,
+		arg.TokenSalt,
+		arg.TokenHash,
 		arg.UserID,
 		arg.CreatedAt,
 		arg.ExpiredAt,
+		arg.Scopes,
+		arg.ProjectID,
 	)
 	var i CreateAPIKeyRow
 	err := row.Scan(
 		&i.Name,
-		&i.Token,
+		&i.TokenPrefix,
 		&i.CreatedAt,
 		&i.ExpiredAt,
+		&i.Scopes,
+		&i.ProjectID,
 	)
 	return i, err
 }
 
-const deleteAPIKey = `-- name: DeleteAPIKey :exec
+const createAPIKeyAuditLog = `-- name: CreateAPIKeyAuditLog :exec
+INSERT INTO api_key_audit_logs(user_id, action, key_type, key_name, key_prefix, new_key_prefix, grace_until, ip_addr)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateAPIKeyAuditLogParams struct {
+	UserID       uuid.UUID
+	Action       string
+	KeyType      string
+	KeyName      pgtype.Text
+	KeyPrefix    string
+	NewKeyPrefix pgtype.Text
+	GraceUntil   pgtype.Timestamptz
+	IpAddr       *netip.Addr
+}
+
+func (q *Queries) CreateAPIKeyAuditLog(ctx context.Context, arg CreateAPIKeyAuditLogParams) error {
+	_, err := q.db.Exec(ctx, createAPIKeyAuditLog,
+		arg.UserID,
+		arg.Action,
+		arg.KeyType,
+		arg.KeyName,
+		arg.KeyPrefix,
+		arg.NewKeyPrefix,
+		arg.GraceUntil,
+		arg.IpAddr,
+	)
+	return err
+}
+
+const deleteAPIKey = `-- name: DeleteAPIKey :one
 DELETE FROM api_keys WHERE user_id = $1 AND id = $2
+RETURNING name, token_prefix
 `
 
 type DeleteAPIKeyParams struct {
@@ -60,21 +105,131 @@ type DeleteAPIKeyParams struct {
 	ID     int32
 }
 
-func (q *Queries) DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) error {
-	_, err := q.db.Exec(ctx, deleteAPIKey, arg.UserID, arg.ID)
-	return err
+type DeleteAPIKeyRow struct {
+	Name        string
+	TokenPrefix string
+}
+
+func (q *Queries) DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) (DeleteAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, deleteAPIKey, arg.UserID, arg.ID)
+	var i DeleteAPIKeyRow
+	err := row.Scan(&i.Name, &i.TokenPrefix)
+	return i, err
+}
+
+const findAPIKeyAuditLogs = `-- name: FindAPIKeyAuditLogs :many
+SELECT action, key_type, key_name, key_prefix, new_key_prefix, grace_until, ip_addr, created_at
+FROM api_key_audit_logs WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type FindAPIKeyAuditLogsParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+type FindAPIKeyAuditLogsRow struct {
+	Action       string
+	KeyType      string
+	KeyName      pgtype.Text
+	KeyPrefix    string
+	NewKeyPrefix pgtype.Text
+	GraceUntil   pgtype.Timestamptz
+	IpAddr       *netip.Addr
+	CreatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) FindAPIKeyAuditLogs(ctx context.Context, arg FindAPIKeyAuditLogsParams) ([]FindAPIKeyAuditLogsRow, error) {
+	rows, err := q.db.Query(ctx, findAPIKeyAuditLogs, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindAPIKeyAuditLogsRow
+	for rows.Next() {
+		var i FindAPIKeyAuditLogsRow
+		if err := rows.Scan(
+			&i.Action,
+			&i.KeyType,
+			&i.KeyName,
+			&i.KeyPrefix,
+			&i.NewKeyPrefix,
+			&i.GraceUntil,
+			&i.IpAddr,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findAPIKeyByID = `-- name: FindAPIKeyByID :one
+SELECT id, name, token_prefix, created_at, expired_at, scopes, project_id, revoked_at, rotated_at
+FROM api_keys WHERE user_id = $1 AND id = $2
+`
+
+type FindAPIKeyByIDParams struct {
+	UserID uuid.UUID
+	ID     int32
+}
+
+type FindAPIKeyByIDRow struct {
+	ID          int32
+	Name        string
+	TokenPrefix string
+	CreatedAt   pgtype.Timestamptz
+	ExpiredAt   pgtype.Timestamptz
+	Scopes      []string
+	ProjectID   pgtype.UUID
+	RevokedAt   pgtype.Timestamptz
+	RotatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) FindAPIKeyByID(ctx context.Context, arg FindAPIKeyByIDParams) (FindAPIKeyByIDRow, error) {
+	row := q.db.QueryRow(ctx, findAPIKeyByID, arg.UserID, arg.ID)
+	var i FindAPIKeyByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.TokenPrefix,
+		&i.CreatedAt,
+		&i.ExpiredAt,
+		&i.Scopes,
+		&i.ProjectID,
+		&i.RevokedAt,
+		&i.RotatedAt,
+	)
+	return i, err
 }
 
 const findAllAPIKeys = `-- name: FindAllAPIKeys :many
-SELECT id, name, token, created_at, expired_at FROM api_keys WHERE user_id = $1
+SELECT k.id, k.name, k.token_prefix, k.created_at, k.expired_at, k.scopes, k.project_id, p.name AS project_name,
+    k.revoked_at, k.last_used_at, k.last_used_ip, k.rotated_at
+FROM api_keys AS k
+LEFT JOIN projects AS p ON k.project_id = p.id
+WHERE k.user_id = $1
+ORDER BY k.created_at
 `
 
 type FindAllAPIKeysRow struct {
-	ID        int32
-	Name      string
-	Token     string
-	CreatedAt pgtype.Timestamptz
-	ExpiredAt pgtype.Timestamptz
+	ID          int32
+	Name        string
+	TokenPrefix string
+	CreatedAt   pgtype.Timestamptz
+	ExpiredAt   pgtype.Timestamptz
+	Scopes      []string
+	ProjectID   pgtype.UUID
+	ProjectName pgtype.Text
+	RevokedAt   pgtype.Timestamptz
+	LastUsedAt  pgtype.Timestamptz
+	LastUsedIp  *netip.Addr
+	RotatedAt   pgtype.Timestamptz
 }
 
 func (q *Queries) FindAllAPIKeys(ctx context.Context, userID uuid.UUID) ([]FindAllAPIKeysRow, error) {
@@ -89,9 +244,16 @@ func (q *Queries) FindAllAPIKeys(ctx context.Context, userID uuid.UUID) ([]FindA
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
-			&i.Token,
+			&i.TokenPrefix,
 			&i.CreatedAt,
 			&i.ExpiredAt,
+			&i.Scopes,
+			&i.ProjectID,
+			&i.ProjectName,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+			&i.LastUsedIp,
+			&i.RotatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -102,3 +264,55 @@ func (q *Queries) FindAllAPIKeys(ctx context.Context, userID uuid.UUID) ([]FindA
 	}
 	return items, nil
 }
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE api_keys SET revoked_at = NOW() WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL
+RETURNING name, token_prefix
+`
+
+type RevokeAPIKeyParams struct {
+	UserID uuid.UUID
+	ID     int32
+}
+
+type RevokeAPIKeyRow struct {
+	Name        string
+	TokenPrefix string
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (RevokeAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, revokeAPIKey, arg.UserID, arg.ID)
+	var i RevokeAPIKeyRow
+	err := row.Scan(&i.Name, &i.TokenPrefix)
+	return i, err
+}
+
+const rotateAPIKey = `-- name: RotateAPIKey :exec
+UPDATE api_keys SET rotated_at = NOW(), expired_at = $3 WHERE user_id = $1 AND id = $2
+`
+
+type RotateAPIKeyParams struct {
+	UserID    uuid.UUID
+	ID        int32
+	ExpiredAt pgtype.Timestamptz
+}
+
+func (q *Queries) RotateAPIKey(ctx context.Context, arg RotateAPIKeyParams) error {
+	_, err := q.db.Exec(ctx, rotateAPIKey, arg.UserID, arg.ID, arg.ExpiredAt)
+	return err
+}
+
+const updateAPIKeyLastUsed = `-- name: UpdateAPIKeyLastUsed :exec
+UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+type UpdateAPIKeyLastUsedParams struct {
+	ID         int32
+	LastUsedIp *netip.Addr
+}
+
+func (q *Queries) UpdateAPIKeyLastUsed(ctx context.Context, arg UpdateAPIKeyLastUsedParams) error {
+	_, err := q.db.Exec(ctx, updateAPIKeyLastUsed, arg.ID, arg.LastUsedIp)
+	return err
+}
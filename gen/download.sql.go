@@ -12,7 +12,7 @@ import (
 )
 
 const downloadIntervalEventData = `-- name: DownloadIntervalEventData :many
-SELECT id, event_type, event_label, page_url, element_path, element_type, ip_addr, user_agent, browser_name, country, region, city, session_id, device_type, time_on_page, screen_resolution, fired_at, received_at, user_id, project_id FROM events 
+SELECT id, event_type, event_label, page_url, element_path, element_type, ip_addr, user_agent, browser_name, country, region, city, session_id, device_type, time_on_page, screen_resolution, fired_at, received_at, user_id, project_id, properties, browser_version, os_name, os_version, is_bot, visitor_hash, distinct_id, anonymous_id, referrer, referrer_domain, channel, utm_source, utm_medium, utm_campaign, utm_term, utm_content, page_path, redactions, trusted, client_fired_at, sample_rate FROM events 
 WHERE user_id = $1 AND project_id = $2
 AND ($3::int = -1 OR received_at >= NOW() - INTERVAL '1 day' * $3::int)
 `
@@ -53,6 +53,27 @@ func (q *Queries) DownloadIntervalEventData(ctx context.Context, arg DownloadInt
 			&i.ReceivedAt,
 			&i.UserID,
 			&i.ProjectID,
+			&i.Properties,
+			&i.BrowserVersion,
+			&i.OsName,
+			&i.OsVersion,
+			&i.IsBot,
+			&i.VisitorHash,
+			&i.DistinctID,
+			&i.AnonymousID,
+			&i.Referrer,
+			&i.ReferrerDomain,
+			&i.Channel,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.PagePath,
+			&i.Redactions,
+			&i.Trusted,
+			&i.ClientFiredAt,
+			&i.SampleRate,
 		); err != nil {
 			return nil, err
 		}
@@ -65,7 +86,7 @@ func (q *Queries) DownloadIntervalEventData(ctx context.Context, arg DownloadInt
 }
 
 const getEventTableHeaders = `-- name: GetEventTableHeaders :many
-SELECT column_name::text FROM information_schema.columns WHERE table_schema = 'public' AND table_name = 'events'
+SELECT column_name::text FROM information_schema.columns WHERE table_schema = 'public' AND table_name = 'events' ORDER BY ordinal_position
 `
 
 func (q *Queries) GetEventTableHeaders(ctx context.Context) ([]string, error) {
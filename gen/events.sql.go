@@ -7,6 +7,7 @@ package gen
 
 import (
 	"context"
+	"encoding/json"
 	"net/netip"
 	"time"
 
@@ -14,6 +15,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countProjectMonthlyEvents = `-- name: CountProjectMonthlyEvents :one
+SELECT COUNT(id) FROM events
+WHERE project_id = $1 AND user_id = $2
+AND received_at > date_trunc('month', NOW())
+`
+
+type CountProjectMonthlyEventsParams struct {
+	ProjectID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func (q *Queries) CountProjectMonthlyEvents(ctx context.Context, arg CountProjectMonthlyEventsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countProjectMonthlyEvents, arg.ProjectID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUserMonthlyEvents = `-- name: CountUserMonthlyEvents :one
 SELECT COUNT(id) FROM events 
 WHERE user_id = $1
@@ -47,7 +66,28 @@ INSERT INTO events (
     fired_at,
     received_at,
     user_id,
-    project_id
+    project_id,
+    properties,
+    browser_version,
+    os_name,
+    os_version,
+    is_bot,
+    visitor_hash,
+    distinct_id,
+    anonymous_id,
+    referrer,
+    referrer_domain,
+    channel,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    utm_term,
+    utm_content,
+    page_path,
+    redactions,
+    trusted,
+    client_fired_at,
+    sample_rate
 ) VALUES (
     $1,  -- event_type
     $2,  -- event_label
@@ -67,7 +107,28 @@ INSERT INTO events (
     $16, -- fired_at
     $17, -- received_at
     $18, -- user_id
-    $19  -- project_id
+    $19, -- project_id
+    $20, -- properties
+    $21, -- browser_version
+    $22, -- os_name
+    $23, -- os_version
+    $24, -- is_bot
+    $25, -- visitor_hash
+    $26, -- distinct_id
+    $27, -- anonymous_id
+    $28, -- referrer
+    $29, -- referrer_domain
+    $30, -- channel
+    $31, -- utm_source
+    $32, -- utm_medium
+    $33, -- utm_campaign
+    $34, -- utm_term
+    $35, -- utm_content
+    $36, -- page_path
+    $37, -- redactions
+    $38, -- trusted
+    $39, -- client_fired_at
+    $40  -- sample_rate
 )
 `
 
@@ -91,6 +152,27 @@ type CreateEventParams struct {
 	ReceivedAt       time.Time
 	UserID           uuid.UUID
 	ProjectID        uuid.UUID
+	Properties       json.RawMessage
+	BrowserVersion   pgtype.Text
+	OsName           pgtype.Text
+	OsVersion        pgtype.Text
+	IsBot            bool
+	VisitorHash      pgtype.Text
+	DistinctID       pgtype.Text
+	AnonymousID      pgtype.Text
+	Referrer         pgtype.Text
+	ReferrerDomain   pgtype.Text
+	Channel          pgtype.Text
+	UtmSource        pgtype.Text
+	UtmMedium        pgtype.Text
+	UtmCampaign      pgtype.Text
+	UtmTerm          pgtype.Text
+	UtmContent       pgtype.Text
+	PagePath         pgtype.Text
+	Redactions       pgtype.Int4
+	Trusted          bool
+	ClientFiredAt    pgtype.Timestamptz
+	SampleRate       float64
 }
 
 func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error {
@@ -114,10 +196,74 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) error
 		arg.ReceivedAt,
 		arg.UserID,
 		arg.ProjectID,
+		arg.Properties,
+		arg.BrowserVersion,
+		arg.OsName,
+		arg.OsVersion,
+		arg.IsBot,
+		arg.VisitorHash,
+		arg.DistinctID,
+		arg.AnonymousID,
+		arg.Referrer,
+		arg.ReferrerDomain,
+		arg.Channel,
+		arg.UtmSource,
+		arg.UtmMedium,
+		arg.UtmCampaign,
+		arg.UtmTerm,
+		arg.UtmContent,
+		arg.PagePath,
+		arg.Redactions,
+		arg.Trusted,
+		arg.ClientFiredAt,
+		arg.SampleRate,
 	)
 	return err
 }
 
+type CreateEventsParams struct {
+	EventType        string
+	EventLabel       pgtype.Text
+	PageUrl          pgtype.Text
+	ElementPath      pgtype.Text
+	ElementType      pgtype.Text
+	IpAddr           *netip.Addr
+	UserAgent        pgtype.Text
+	BrowserName      pgtype.Text
+	Country          pgtype.Text
+	Region           pgtype.Text
+	City             pgtype.Text
+	SessionID        pgtype.Text
+	DeviceType       pgtype.Text
+	TimeOnPage       pgtype.Int4
+	ScreenResolution pgtype.Text
+	FiredAt          time.Time
+	ReceivedAt       time.Time
+	UserID           uuid.UUID
+	ProjectID        uuid.UUID
+	Properties       json.RawMessage
+	BrowserVersion   pgtype.Text
+	OsName           pgtype.Text
+	OsVersion        pgtype.Text
+	IsBot            bool
+	VisitorHash      pgtype.Text
+	DistinctID       pgtype.Text
+	AnonymousID      pgtype.Text
+	Referrer         pgtype.Text
+	ReferrerDomain   pgtype.Text
+	Channel          pgtype.Text
+	UtmSource        pgtype.Text
+	UtmMedium        pgtype.Text
+	UtmCampaign      pgtype.Text
+	UtmTerm          pgtype.Text
+	UtmContent       pgtype.Text
+	PagePath         pgtype.Text
+	Redactions       pgtype.Int4
+	Trusted          bool
+	ClientFiredAt    pgtype.Timestamptz
+	SampleRate       float64
+}
+
 const deleteEventByProjectID = `-- name: DeleteEventByProjectID :exec
 DELETE FROM events WHERE user_id = $1 AND project_id = $2
 `
@@ -132,9 +278,63 @@ func (q *Queries) DeleteEventByProjectID(ctx context.Context, arg DeleteEventByP
 	return err
 }
 
+const getEventPropertyBreakdown = `-- name: GetEventPropertyBreakdown :many
+SELECT
+    (e.properties ->> $2::text)::text AS value,
+    ROUND(SUM(1 / e.sample_rate))::bigint AS total
+FROM events AS e
+WHERE e.user_id = $1
+AND ($3::int = -1 OR e.received_at >= NOW() - INTERVAL '1 day' * $3::int)
+AND ($4::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = $4) 
+AND e.properties ->> $2::text IS NOT NULL
+GROUP BY value
+ORDER BY total DESC
+LIMIT COALESCE($5::integer, 100)
+`
+
+type GetEventPropertyBreakdownParams struct {
+	UserID      uuid.UUID
+	PropertyKey string
+	Interval    int32
+	ProjectID   uuid.UUID
+	LimitCount  int32
+}
+
+type GetEventPropertyBreakdownRow struct {
+	Value string
+	Total int64
+}
+
+func (q *Queries) GetEventPropertyBreakdown(ctx context.Context, arg GetEventPropertyBreakdownParams) ([]GetEventPropertyBreakdownRow, error) {
+	rows, err := q.db.Query(ctx, getEventPropertyBreakdown,
+		arg.UserID,
+		arg.PropertyKey,
+		arg.Interval,
+		arg.ProjectID,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEventPropertyBreakdownRow
+	for rows.Next() {
+		var i GetEventPropertyBreakdownRow
+		if err := rows.Scan(&i.Value, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getEvents = `-- name: GetEvents :many
 SELECT
     p.name AS project_name,
+    e.id,
     e.event_type,
     e.event_label,
     e.page_url,
@@ -152,25 +352,70 @@ SELECT
     e.screen_resolution,
     e.fired_at,
     e.received_at,
-    e.project_id
+    e.project_id,
+    e.properties,
+    e.browser_version,
+    e.os_name,
+    e.os_version,
+    e.is_bot,
+    e.visitor_hash,
+    e.distinct_id,
+    e.anonymous_id,
+    e.referrer,
+    e.referrer_domain,
+    e.channel,
+    e.utm_source,
+    e.utm_medium,
+    e.utm_campaign,
+    e.utm_term,
+    e.utm_content,
+    e.page_path,
+    e.redactions,
+    e.trusted,
+    e.client_fired_at,
+    e.sample_rate
 FROM events AS e
 JOIN projects AS p ON e.project_id = p.id
 WHERE e.user_id = $1
 AND ($2::int = -1 OR received_at >= NOW() - INTERVAL '1 day' * $2::int)
 AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = $3) 
-ORDER BY e.received_at DESC
-LIMIT COALESCE($4::integer, 100)
+AND ($4::jsonb = '{}'::jsonb OR e.properties @> $4::jsonb)
+AND ($5::bool IS NULL OR e.trusted = $5)
+AND ($6::timestamptz IS NULL OR e.received_at >= $6)
+AND ($7::timestamptz IS NULL OR e.received_at < $7)
+AND ($8::text IS NULL OR e.event_type = $8)
+AND ($9::text IS NULL OR e.event_label = $9)
+AND ($10::text IS NULL OR e.country = $10)
+AND ($11::text IS NULL OR e.session_id = $11)
+AND ($12::text IS NULL OR e.page_url LIKE $12 || '%')
+AND ($13::text IS NULL OR e.device_type = $13)
+AND ($14::timestamptz IS NULL OR (e.received_at, e.id) < ($14, $15::uuid))
+ORDER BY e.received_at DESC, e.id DESC
+LIMIT COALESCE($16::integer, 100)
 `
 
 type GetEventsParams struct {
-	UserID     uuid.UUID
-	Interval   int32
-	ProjectID  uuid.UUID
-	LimitCount int32
+	UserID           uuid.UUID
+	Interval         int32
+	ProjectID        uuid.UUID
+	Properties       []byte
+	Trusted          pgtype.Bool
+	ReceivedFrom     pgtype.Timestamptz
+	ReceivedTo       pgtype.Timestamptz
+	EventType        pgtype.Text
+	EventLabel       pgtype.Text
+	Country          pgtype.Text
+	SessionID        pgtype.Text
+	PageUrlPrefix    pgtype.Text
+	DeviceType       pgtype.Text
+	CursorReceivedAt pgtype.Timestamptz
+	CursorID         pgtype.UUID
+	LimitCount       int32
 }
 
 type GetEventsRow struct {
 	ProjectName      string
+	ID               uuid.UUID
 	EventType        string
 	EventLabel       pgtype.Text
 	PageUrl          pgtype.Text
@@ -189,6 +434,27 @@ type GetEventsRow struct {
 	FiredAt          time.Time
 	ReceivedAt       time.Time
 	ProjectID        uuid.UUID
+	Properties       json.RawMessage
+	BrowserVersion   pgtype.Text
+	OsName           pgtype.Text
+	OsVersion        pgtype.Text
+	IsBot            bool
+	VisitorHash      pgtype.Text
+	DistinctID       pgtype.Text
+	AnonymousID      pgtype.Text
+	Referrer         pgtype.Text
+	ReferrerDomain   pgtype.Text
+	Channel          pgtype.Text
+	UtmSource        pgtype.Text
+	UtmMedium        pgtype.Text
+	UtmCampaign      pgtype.Text
+	UtmTerm          pgtype.Text
+	UtmContent       pgtype.Text
+	PagePath         pgtype.Text
+	Redactions       pgtype.Int4
+	Trusted          bool
+	ClientFiredAt    pgtype.Timestamptz
+	SampleRate       float64
 }
 
 // check if project id is provided and is not default empty UUID
@@ -197,6 +463,18 @@ func (q *Queries) GetEvents(ctx context.Context, arg GetEventsParams) ([]GetEven
 		arg.UserID,
 		arg.Interval,
 		arg.ProjectID,
+		arg.Properties,
+		arg.Trusted,
+		arg.ReceivedFrom,
+		arg.ReceivedTo,
+		arg.EventType,
+		arg.EventLabel,
+		arg.Country,
+		arg.SessionID,
+		arg.PageUrlPrefix,
+		arg.DeviceType,
+		arg.CursorReceivedAt,
+		arg.CursorID,
 		arg.LimitCount,
 	)
 	if err != nil {
@@ -208,6 +486,213 @@ func (q *Queries) GetEvents(ctx context.Context, arg GetEventsParams) ([]GetEven
 		var i GetEventsRow
 		if err := rows.Scan(
 			&i.ProjectName,
+			&i.ID,
+			&i.EventType,
+			&i.EventLabel,
+			&i.PageUrl,
+			&i.ElementPath,
+			&i.ElementType,
+			&i.IpAddr,
+			&i.UserAgent,
+			&i.BrowserName,
+			&i.Country,
+			&i.Region,
+			&i.City,
+			&i.SessionID,
+			&i.DeviceType,
+			&i.TimeOnPage,
+			&i.ScreenResolution,
+			&i.FiredAt,
+			&i.ReceivedAt,
+			&i.ProjectID,
+			&i.Properties,
+			&i.BrowserVersion,
+			&i.OsName,
+			&i.OsVersion,
+			&i.IsBot,
+			&i.VisitorHash,
+			&i.DistinctID,
+			&i.AnonymousID,
+			&i.Referrer,
+			&i.ReferrerDomain,
+			&i.Channel,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.PagePath,
+			&i.Redactions,
+			&i.Trusted,
+			&i.ClientFiredAt,
+			&i.SampleRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEventsAscending = `-- name: GetEventsAscending :many
+SELECT
+    p.name AS project_name,
+    e.id,
+    e.event_type,
+    e.event_label,
+    e.page_url,
+    e.element_path,
+    e.element_type,
+    e.ip_addr,
+    e.user_agent,
+    e.browser_name,
+    e.country,
+    e.region,
+    e.city,
+    e.session_id,
+    e.device_type,
+    e.time_on_page,
+    e.screen_resolution,
+    e.fired_at,
+    e.received_at,
+    e.project_id,
+    e.properties,
+    e.browser_version,
+    e.os_name,
+    e.os_version,
+    e.is_bot,
+    e.visitor_hash,
+    e.distinct_id,
+    e.anonymous_id,
+    e.referrer,
+    e.referrer_domain,
+    e.channel,
+    e.utm_source,
+    e.utm_medium,
+    e.utm_campaign,
+    e.utm_term,
+    e.utm_content,
+    e.page_path,
+    e.redactions,
+    e.trusted,
+    e.client_fired_at,
+    e.sample_rate
+FROM events AS e
+JOIN projects AS p ON e.project_id = p.id
+WHERE e.user_id = $1
+AND ($2::int = -1 OR received_at >= NOW() - INTERVAL '1 day' * $2::int)
+AND ($3::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = $3) 
+AND ($4::jsonb = '{}'::jsonb OR e.properties @> $4::jsonb)
+AND ($5::bool IS NULL OR e.trusted = $5)
+AND ($6::timestamptz IS NULL OR e.received_at >= $6)
+AND ($7::timestamptz IS NULL OR e.received_at < $7)
+AND ($8::text IS NULL OR e.event_type = $8)
+AND ($9::text IS NULL OR e.event_label = $9)
+AND ($10::text IS NULL OR e.country = $10)
+AND ($11::text IS NULL OR e.session_id = $11)
+AND ($12::text IS NULL OR e.page_url LIKE $12 || '%')
+AND ($13::text IS NULL OR e.device_type = $13)
+AND ($14::timestamptz IS NULL OR (e.received_at, e.id) > ($14, $15::uuid))
+ORDER BY e.received_at ASC, e.id ASC
+LIMIT COALESCE($16::integer, 100)
+`
+
+type GetEventsAscendingParams struct {
+	UserID           uuid.UUID
+	Interval         int32
+	ProjectID        uuid.UUID
+	Properties       []byte
+	Trusted          pgtype.Bool
+	ReceivedFrom     pgtype.Timestamptz
+	ReceivedTo       pgtype.Timestamptz
+	EventType        pgtype.Text
+	EventLabel       pgtype.Text
+	Country          pgtype.Text
+	SessionID        pgtype.Text
+	PageUrlPrefix    pgtype.Text
+	DeviceType       pgtype.Text
+	CursorReceivedAt pgtype.Timestamptz
+	CursorID         pgtype.UUID
+	LimitCount       int32
+}
+
+type GetEventsAscendingRow struct {
+	ProjectName      string
+	ID               uuid.UUID
+	EventType        string
+	EventLabel       pgtype.Text
+	PageUrl          pgtype.Text
+	ElementPath      pgtype.Text
+	ElementType      pgtype.Text
+	IpAddr           *netip.Addr
+	UserAgent        pgtype.Text
+	BrowserName      pgtype.Text
+	Country          pgtype.Text
+	Region           pgtype.Text
+	City             pgtype.Text
+	SessionID        pgtype.Text
+	DeviceType       pgtype.Text
+	TimeOnPage       pgtype.Int4
+	ScreenResolution pgtype.Text
+	FiredAt          time.Time
+	ReceivedAt       time.Time
+	ProjectID        uuid.UUID
+	Properties       json.RawMessage
+	BrowserVersion   pgtype.Text
+	OsName           pgtype.Text
+	OsVersion        pgtype.Text
+	IsBot            bool
+	VisitorHash      pgtype.Text
+	DistinctID       pgtype.Text
+	AnonymousID      pgtype.Text
+	Referrer         pgtype.Text
+	ReferrerDomain   pgtype.Text
+	Channel          pgtype.Text
+	UtmSource        pgtype.Text
+	UtmMedium        pgtype.Text
+	UtmCampaign      pgtype.Text
+	UtmTerm          pgtype.Text
+	UtmContent       pgtype.Text
+	PagePath         pgtype.Text
+	Redactions       pgtype.Int4
+	Trusted          bool
+	ClientFiredAt    pgtype.Timestamptz
+	SampleRate       float64
+}
+
+// check if project id is provided and is not default empty UUID
+func (q *Queries) GetEventsAscending(ctx context.Context, arg GetEventsAscendingParams) ([]GetEventsAscendingRow, error) {
+	rows, err := q.db.Query(ctx, getEventsAscending,
+		arg.UserID,
+		arg.Interval,
+		arg.ProjectID,
+		arg.Properties,
+		arg.Trusted,
+		arg.ReceivedFrom,
+		arg.ReceivedTo,
+		arg.EventType,
+		arg.EventLabel,
+		arg.Country,
+		arg.SessionID,
+		arg.PageUrlPrefix,
+		arg.DeviceType,
+		arg.CursorReceivedAt,
+		arg.CursorID,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEventsAscendingRow
+	for rows.Next() {
+		var i GetEventsAscendingRow
+		if err := rows.Scan(
+			&i.ProjectName,
+			&i.ID,
 			&i.EventType,
 			&i.EventLabel,
 			&i.PageUrl,
@@ -226,6 +711,27 @@ func (q *Queries) GetEvents(ctx context.Context, arg GetEventsParams) ([]GetEven
 			&i.FiredAt,
 			&i.ReceivedAt,
 			&i.ProjectID,
+			&i.Properties,
+			&i.BrowserVersion,
+			&i.OsName,
+			&i.OsVersion,
+			&i.IsBot,
+			&i.VisitorHash,
+			&i.DistinctID,
+			&i.AnonymousID,
+			&i.Referrer,
+			&i.ReferrerDomain,
+			&i.Channel,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.PagePath,
+			&i.Redactions,
+			&i.Trusted,
+			&i.ClientFiredAt,
+			&i.SampleRate,
 		); err != nil {
 			return nil, err
 		}
@@ -316,15 +822,17 @@ FROM events
 WHERE
     user_id = $2 AND project_id = $1 
     AND ($3::bool IS NOT TRUE OR received_at >= NOW() - INTERVAL '1 minute')
+    AND ($4::bool IS NOT TRUE OR trusted = TRUE)
 ORDER BY received_at DESC
-LIMIT COALESCE($4::integer, 100)
+LIMIT COALESCE($5::integer, 100)
 `
 
 type GetLiveEventsDetailParams struct {
-	ProjectID  uuid.UUID
-	UserID     uuid.UUID
-	Bylasthour bool
-	LimitCount int32
+	ProjectID   uuid.UUID
+	UserID      uuid.UUID
+	Bylasthour  bool
+	Trustedonly bool
+	LimitCount  int32
 }
 
 type GetLiveEventsDetailRow struct {
@@ -351,6 +859,7 @@ func (q *Queries) GetLiveEventsDetail(ctx context.Context, arg GetLiveEventsDeta
 		arg.ProjectID,
 		arg.UserID,
 		arg.Bylasthour,
+		arg.Trustedonly,
 		arg.LimitCount,
 	)
 	if err != nil {
@@ -389,9 +898,9 @@ func (q *Queries) GetLiveEventsDetail(ctx context.Context, arg GetLiveEventsDeta
 }
 
 const getPercentageEventsLabel = `-- name: GetPercentageEventsLabel :many
-SELECT event_label, count(event_label) AS total
+SELECT event_label, COALESCE(ROUND(SUM(1 / sample_rate) FILTER (WHERE event_label IS NOT NULL)), 0)::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE
 GROUP BY event_label
 ORDER BY total DESC
 LIMIT 10
@@ -428,9 +937,9 @@ func (q *Queries) GetPercentageEventsLabel(ctx context.Context, arg GetPercentag
 }
 
 const getPercentageEventsType = `-- name: GetPercentageEventsType :many
-SELECT event_type, COUNT(*) AS total
+SELECT event_type, ROUND(SUM(1 / sample_rate))::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE
 GROUP BY event_type
 ORDER BY total DESC
 LIMIT 10
@@ -469,9 +978,9 @@ func (q *Queries) GetPercentageEventsType(ctx context.Context, arg GetPercentage
 const getWeeklyEvents = `-- name: GetWeeklyEvents :many
 SELECT
   DATE_TRUNC('day', received_at)::timestamp AS timestamp,
-  COUNT(*) AS total
+  ROUND(SUM(1 / sample_rate))::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1 AND received_at >= NOW() - INTERVAL '7 days'
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE AND received_at >= NOW() - INTERVAL '7 days'
 GROUP BY timestamp ORDER BY timestamp ASC
 `
 
@@ -507,9 +1016,9 @@ func (q *Queries) GetWeeklyEvents(ctx context.Context, arg GetWeeklyEventsParams
 
 const getWeeklyEventsTotal = `-- name: GetWeeklyEventsTotal :one
 SELECT
-COUNT(id) AS total
+COALESCE(ROUND(SUM(1 / sample_rate)), 0)::bigint AS total
 FROM events WHERE received_at >= NOW() - INTERVAL '7 days'
-AND user_id = $2 AND project_id = $1
+AND user_id = $2 AND project_id = $1 AND is_bot = FALSE
 `
 
 type GetWeeklyEventsTotalParams struct {
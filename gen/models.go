@@ -5,6 +5,7 @@
 package gen
 
 import (
+	"encoding/json"
 	"net/netip"
 	"time"
 
@@ -13,12 +14,33 @@ import (
 )
 
 type ApiKey struct {
-	ID        int32
-	Name      string
-	Token     string
-	UserID    uuid.UUID
-	CreatedAt pgtype.Timestamptz
-	ExpiredAt pgtype.Timestamptz
+	ID          int32
+	Name        string
+	UserID      uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+	ExpiredAt   pgtype.Timestamptz
+	Scopes      []string
+	ProjectID   pgtype.UUID
+	RevokedAt   pgtype.Timestamptz
+	LastUsedAt  pgtype.Timestamptz
+	LastUsedIp  *netip.Addr
+	TokenPrefix string
+	TokenSalt   string
+	TokenHash   string
+	RotatedAt   pgtype.Timestamptz
+}
+
+type ApiKeyAuditLog struct {
+	ID           int64
+	UserID       uuid.UUID
+	Action       string
+	KeyType      string
+	KeyName      pgtype.Text
+	KeyPrefix    string
+	NewKeyPrefix pgtype.Text
+	GraceUntil   pgtype.Timestamptz
+	IpAddr       *netip.Addr
+	CreatedAt    pgtype.Timestamptz
 }
 
 type Event struct {
@@ -42,16 +64,63 @@ type Event struct {
 	ReceivedAt       time.Time
 	UserID           uuid.UUID
 	ProjectID        uuid.UUID
+	Properties       json.RawMessage
+	BrowserVersion   pgtype.Text
+	OsName           pgtype.Text
+	OsVersion        pgtype.Text
+	IsBot            bool
+	VisitorHash      pgtype.Text
+	DistinctID       pgtype.Text
+	AnonymousID      pgtype.Text
+	Referrer         pgtype.Text
+	ReferrerDomain   pgtype.Text
+	Channel          pgtype.Text
+	UtmSource        pgtype.Text
+	UtmMedium        pgtype.Text
+	UtmCampaign      pgtype.Text
+	UtmTerm          pgtype.Text
+	UtmContent       pgtype.Text
+	PagePath         pgtype.Text
+	Redactions       pgtype.Int4
+	Trusted          bool
+	ClientFiredAt    pgtype.Timestamptz
+	SampleRate       float64
 }
 
-type Project struct {
-	ID          uuid.UUID
-	Name        string
-	Description pgtype.Text
-	Url         pgtype.Text
+type IdentityAlias struct {
+	ProjectID   uuid.UUID
+	AnonymousID string
+	DistinctID  string
 	UserID      uuid.UUID
-	CreatedAt   pgtype.Timestamptz
-	DeletedAt   pgtype.Timestamptz
+	CreatedAt   time.Time
+}
+
+type Project struct {
+	ID                  uuid.UUID
+	Name                string
+	Description         pgtype.Text
+	Url                 pgtype.Text
+	UserID              uuid.UUID
+	CreatedAt           pgtype.Timestamptz
+	DeletedAt           pgtype.Timestamptz
+	BotPolicy           string
+	AllowedOrigins      []string
+	IpMode              string
+	GeoCountryOnly      bool
+	PrivacySignalPolicy string
+	SuppressedEvents    int64
+	UrlQueryAllowlist   []string
+	UrlPathPatterns     []string
+	RedactDetectors     []string
+	RedactPatterns      []string
+	RedactedValues      int64
+	Processors          []string
+	SigningSecret       pgtype.Text
+	RateLimit           int32
+	RateLimitBurst      int32
+	MonthlyQuota        int64
+	TimestampPolicy     string
+	SampleRules         []string
 }
 
 type ProjectAggregation struct {
@@ -78,14 +147,16 @@ type ProjectAggregation struct {
 }
 
 type User struct {
-	ID             uuid.UUID
-	Fullname       string
-	Email          string
-	PasswordHashed string
-	RootUser       bool
-	ProfileUrl     pgtype.Text
-	PublicKey      string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	DeletedAt      pgtype.Timestamptz
+	ID                         uuid.UUID
+	Fullname                   string
+	Email                      string
+	PasswordHashed             string
+	RootUser                   bool
+	ProfileUrl                 pgtype.Text
+	PublicKey                  string
+	CreatedAt                  pgtype.Timestamptz
+	UpdatedAt                  pgtype.Timestamptz
+	DeletedAt                  pgtype.Timestamptz
+	PreviousPublicKey          pgtype.Text
+	PreviousPublicKeyExpiredAt pgtype.Timestamptz
 }
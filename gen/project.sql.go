@@ -219,15 +219,33 @@ func (q *Queries) DeleteProject(ctx context.Context, arg DeleteProjectParams) er
 }
 
 const findAllProjects = `-- name: FindAllProjects :many
-SELECT id, name, description, url, created_at FROM projects WHERE user_id = $1 AND deleted_at IS NULL
+SELECT id, name, description, url, created_at, bot_policy, allowed_origins, ip_mode, geo_country_only, privacy_signal_policy, url_query_allowlist, url_path_patterns, redact_detectors, redact_patterns, processors, rate_limit, rate_limit_burst, monthly_quota, timestamp_policy, sample_rules, signing_secret, suppressed_events, redacted_values FROM projects WHERE user_id = $1 AND deleted_at IS NULL
 `
 
 type FindAllProjectsRow struct {
-	ID          uuid.UUID
-	Name        string
-	Description pgtype.Text
-	Url         pgtype.Text
-	CreatedAt   pgtype.Timestamptz
+	ID                  uuid.UUID
+	Name                string
+	Description         pgtype.Text
+	Url                 pgtype.Text
+	CreatedAt           pgtype.Timestamptz
+	BotPolicy           string
+	AllowedOrigins      []string
+	IpMode              string
+	GeoCountryOnly      bool
+	PrivacySignalPolicy string
+	UrlQueryAllowlist   []string
+	UrlPathPatterns     []string
+	RedactDetectors     []string
+	RedactPatterns      []string
+	Processors          []string
+	RateLimit           int32
+	RateLimitBurst      int32
+	MonthlyQuota        int64
+	TimestampPolicy     string
+	SampleRules         []string
+	SigningSecret       pgtype.Text
+	SuppressedEvents    int64
+	RedactedValues      int64
 }
 
 func (q *Queries) FindAllProjects(ctx context.Context, userID uuid.UUID) ([]FindAllProjectsRow, error) {
@@ -245,6 +263,24 @@ func (q *Queries) FindAllProjects(ctx context.Context, userID uuid.UUID) ([]Find
 			&i.Description,
 			&i.Url,
 			&i.CreatedAt,
+			&i.BotPolicy,
+			&i.AllowedOrigins,
+			&i.IpMode,
+			&i.GeoCountryOnly,
+			&i.PrivacySignalPolicy,
+			&i.UrlQueryAllowlist,
+			&i.UrlPathPatterns,
+			&i.RedactDetectors,
+			&i.RedactPatterns,
+			&i.Processors,
+			&i.RateLimit,
+			&i.RateLimitBurst,
+			&i.MonthlyQuota,
+			&i.TimestampPolicy,
+			&i.SampleRules,
+			&i.SigningSecret,
+			&i.SuppressedEvents,
+			&i.RedactedValues,
 		); err != nil {
 			return nil, err
 		}
@@ -339,6 +375,92 @@ func (q *Queries) FindProjectByID(ctx context.Context, arg FindProjectByIDParams
 	return i, err
 }
 
+const findProjectSettings = `-- name: FindProjectSettings :one
+SELECT id, url, bot_policy, allowed_origins, ip_mode, geo_country_only, privacy_signal_policy, url_query_allowlist, url_path_patterns, redact_detectors, redact_patterns, processors, rate_limit, rate_limit_burst, monthly_quota, timestamp_policy, sample_rules, signing_secret FROM projects WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type FindProjectSettingsParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type FindProjectSettingsRow struct {
+	ID                  uuid.UUID
+	Url                 pgtype.Text
+	BotPolicy           string
+	AllowedOrigins      []string
+	IpMode              string
+	GeoCountryOnly      bool
+	PrivacySignalPolicy string
+	UrlQueryAllowlist   []string
+	UrlPathPatterns     []string
+	RedactDetectors     []string
+	RedactPatterns      []string
+	Processors          []string
+	RateLimit           int32
+	RateLimitBurst      int32
+	MonthlyQuota        int64
+	TimestampPolicy     string
+	SampleRules         []string
+	SigningSecret       pgtype.Text
+}
+
+func (q *Queries) FindProjectSettings(ctx context.Context, arg FindProjectSettingsParams) (FindProjectSettingsRow, error) {
+	row := q.db.QueryRow(ctx, findProjectSettings, arg.ID, arg.UserID)
+	var i FindProjectSettingsRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.BotPolicy,
+		&i.AllowedOrigins,
+		&i.IpMode,
+		&i.GeoCountryOnly,
+		&i.PrivacySignalPolicy,
+		&i.UrlQueryAllowlist,
+		&i.UrlPathPatterns,
+		&i.RedactDetectors,
+		&i.RedactPatterns,
+		&i.Processors,
+		&i.RateLimit,
+		&i.RateLimitBurst,
+		&i.MonthlyQuota,
+		&i.TimestampPolicy,
+		&i.SampleRules,
+		&i.SigningSecret,
+	)
+	return i, err
+}
+
+const incrementRedactedValues = `-- name: IncrementRedactedValues :exec
+UPDATE projects SET redacted_values = redacted_values + $3 WHERE id = $1 AND user_id = $2
+`
+
+type IncrementRedactedValuesParams struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	RedactedValues int64
+}
+
+func (q *Queries) IncrementRedactedValues(ctx context.Context, arg IncrementRedactedValuesParams) error {
+	_, err := q.db.Exec(ctx, incrementRedactedValues, arg.ID, arg.UserID, arg.RedactedValues)
+	return err
+}
+
+const incrementSuppressedEvents = `-- name: IncrementSuppressedEvents :exec
+UPDATE projects SET suppressed_events = suppressed_events + $3 WHERE id = $1 AND user_id = $2
+`
+
+type IncrementSuppressedEventsParams struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	SuppressedEvents int64
+}
+
+func (q *Queries) IncrementSuppressedEvents(ctx context.Context, arg IncrementSuppressedEventsParams) error {
+	_, err := q.db.Exec(ctx, incrementSuppressedEvents, arg.ID, arg.UserID, arg.SuppressedEvents)
+	return err
+}
+
 const lastProjectDataReceived = `-- name: LastProjectDataReceived :one
 SELECT received_at FROM events 
 WHERE user_id = $1 AND project_id = $2
@@ -379,3 +501,81 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) er
 	)
 	return err
 }
+
+const updateProjectSettings = `-- name: UpdateProjectSettings :exec
+UPDATE projects SET
+    bot_policy = $1,
+    allowed_origins = $2,
+    ip_mode = $3,
+    geo_country_only = $4,
+    privacy_signal_policy = $5,
+    url_query_allowlist = $6,
+    url_path_patterns = $7,
+    redact_detectors = $8,
+    redact_patterns = $9,
+    processors = $10,
+    rate_limit = $11,
+    rate_limit_burst = $12,
+    monthly_quota = $13,
+    timestamp_policy = $14,
+    sample_rules = $15
+WHERE id = $16 AND user_id = $17 AND deleted_at IS NULL
+`
+
+type UpdateProjectSettingsParams struct {
+	BotPolicy           string
+	AllowedOrigins      []string
+	IpMode              string
+	GeoCountryOnly      bool
+	PrivacySignalPolicy string
+	UrlQueryAllowlist   []string
+	UrlPathPatterns     []string
+	RedactDetectors     []string
+	RedactPatterns      []string
+	Processors          []string
+	RateLimit           int32
+	RateLimitBurst      int32
+	MonthlyQuota        int64
+	TimestampPolicy     string
+	SampleRules         []string
+	ID                  uuid.UUID
+	UserID              uuid.UUID
+}
+
+func (q *Queries) UpdateProjectSettings(ctx context.Context, arg UpdateProjectSettingsParams) error {
+	_, err := q.db.Exec(ctx, updateProjectSettings,
+		arg.BotPolicy,
+		arg.AllowedOrigins,
+		arg.IpMode,
+		arg.GeoCountryOnly,
+		arg.PrivacySignalPolicy,
+		arg.UrlQueryAllowlist,
+		arg.UrlPathPatterns,
+		arg.RedactDetectors,
+		arg.RedactPatterns,
+		arg.Processors,
+		arg.RateLimit,
+		arg.RateLimitBurst,
+		arg.MonthlyQuota,
+		arg.TimestampPolicy,
+		arg.SampleRules,
+		arg.ID,
+		arg.UserID,
+	)
+	return err
+}
+
+const updateProjectSigningSecret = `-- name: UpdateProjectSigningSecret :exec
+UPDATE projects SET signing_secret = $3 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+`
+
+type UpdateProjectSigningSecretParams struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	SigningSecret pgtype.Text
+}
+
+func (q *Queries) UpdateProjectSigningSecret(ctx context.Context, arg UpdateProjectSigningSecretParams) error {
+	_, err := q.db.Exec(ctx, updateProjectSigningSecret, arg.ID, arg.UserID, arg.SigningSecret)
+	return err
+}
@@ -138,10 +138,10 @@ func (q *Queries) FindUserByID(ctx context.Context, id uuid.UUID) (FindUserByIDR
 	return i, err
 }
 
-const findUserByPrivateKey = `-- name: FindUserByPrivateKey :one
-SELECT u.id, u.fullname, u.email, u.profile_url FROM users AS u
+const findUserByPrivateKey = `-- name: FindUserByPrivateKey :many
+SELECT u.id, u.fullname, u.email, u.profile_url, k.id AS key_id, k.scopes, k.project_id, k.token_salt, k.token_hash, k.expired_at, k.revoked_at FROM users AS u
 JOIN api_keys AS k ON u.id = k.user_id
-WHERE k.token = $1
+WHERE k.token_prefix = $1
 `
 
 type FindUserByPrivateKeyRow struct {
@@ -149,22 +149,51 @@ type FindUserByPrivateKeyRow struct {
 	Fullname   string
 	Email      string
 	ProfileUrl pgtype.Text
-}
-
-func (q *Queries) FindUserByPrivateKey(ctx context.Context, token string) (FindUserByPrivateKeyRow, error) {
-	row := q.db.QueryRow(ctx, findUserByPrivateKey, token)
-	var i FindUserByPrivateKeyRow
-	err := row.Scan(
-		&i.ID,
-		&i.Fullname,
-		&i.Email,
-		&i.ProfileUrl,
-	)
-	return i, err
+	KeyID      int32
+	Scopes     []string
+	ProjectID  pgtype.UUID
+	TokenSalt  string
+	TokenHash  string
+	ExpiredAt  pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) FindUserByPrivateKey(ctx context.Context, tokenPrefix string) ([]FindUserByPrivateKeyRow, error) {
+	rows, err := q.db.Query(ctx, findUserByPrivateKey, tokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindUserByPrivateKeyRow
+	for rows.Next() {
+		var i FindUserByPrivateKeyRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Fullname,
+			&i.Email,
+			&i.ProfileUrl,
+			&i.KeyID,
+			&i.Scopes,
+			&i.ProjectID,
+			&i.TokenSalt,
+			&i.TokenHash,
+			&i.ExpiredAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const findUserByPublicKey = `-- name: FindUserByPublicKey :one
-SELECT id, fullname, email, profile_url FROM users WHERE public_key = $1
+SELECT id, fullname, email, profile_url FROM users
+WHERE public_key = $1
+OR (previous_public_key = $1 AND previous_public_key_expired_at > NOW())
 `
 
 type FindUserByPublicKeyRow struct {
@@ -196,3 +225,22 @@ func (q *Queries) FindUserPublicKey(ctx context.Context, id uuid.UUID) (string,
 	err := row.Scan(&public_key)
 	return public_key, err
 }
+
+const rotateUserPublicKey = `-- name: RotateUserPublicKey :exec
+UPDATE users SET
+    previous_public_key = public_key,
+    previous_public_key_expired_at = $2,
+    public_key = $3
+WHERE id = $1
+`
+
+type RotateUserPublicKeyParams struct {
+	ID                         uuid.UUID
+	PreviousPublicKeyExpiredAt pgtype.Timestamptz
+	PublicKey                  string
+}
+
+func (q *Queries) RotateUserPublicKey(ctx context.Context, arg RotateUserPublicKeyParams) error {
+	_, err := q.db.Exec(ctx, rotateUserPublicKey, arg.ID, arg.PreviousPublicKeyExpiredAt, arg.PublicKey)
+	return err
+}
@@ -27,3 +27,188 @@ var Intervals = map[string]int{
 var WORKER_POOL_COUNT = 10
 var WORKER_BUFFER_SIZE = 1000
 var WORKER_TIME_TICKER = time.Second * 5
+
+// maximum number of events accepted by a single batch ingestion request.
+var MAX_BATCH_EVENTS = 100
+
+// maximum number of events returned by a single page of GET /api/v1/events.
+var MAX_EVENTS_PAGE_SIZE = 1000
+
+// default and maximum lifetime of private API keys in days, 0 creates a key that never expires.
+var API_KEY_EXPIRY_DAYS = 180
+var MAX_API_KEY_EXPIRY_DAYS = 730
+
+// length of the start of private API keys stored in plaintext to find their hashes, e.g. snt_AbCd1234.
+const API_KEY_PREFIX_LENGTH = 12
+
+// default and maximum time a rotated key keeps working next to its replacement.
+var API_KEY_ROTATION_GRACE = 24 * time.Hour
+var MAX_API_KEY_ROTATION_GRACE = 30 * 24 * time.Hour
+
+// number of audit trail entries shown on the API keys page.
+const API_KEY_AUDIT_LOG_LIMIT = 50
+
+// maximum size in bytes of a gzip, br or deflate request body once decompressed.
+var MAX_DECOMPRESSED_BODY_SIZE int64 = 4 * 1024 * 1024
+
+// maximum number of query parameters and path templates in a project's URL rules.
+var MAX_URL_RULES = 50
+
+// maximum number of sample rules per project.
+var MAX_SAMPLE_RULES = 50
+
+// maximum number of custom redaction patterns per project, and the maximum length of each.
+var MAX_REDACT_PATTERNS = 20
+var MAX_REDACT_PATTERN_LENGTH = 255
+
+// REDACTED_VALUE replaces personal data found in event fields.
+var REDACTED_VALUE = "[REDACTED]"
+
+// lifetime of the daily salt used to hash visitors, once expired the hashes can not be linked back to an IP.
+var VISITOR_SALT_TTL = 24 * time.Hour
+
+// maximum number of allowed origins per project, besides the project url.
+var MAX_ALLOWED_ORIGINS = 20
+
+// maximum age of a signed request's timestamp, in both directions to allow for clock drift.
+// Signatures are remembered for twice this window to reject replayed requests.
+var SIGNATURE_TOLERANCE = 5 * time.Minute
+
+// headers of signed server-to-server ingestion requests.
+const (
+	HEADER_SIGNATURE           = "X-Sentinel-Signature"
+	HEADER_SIGNATURE_TIMESTAMP = "X-Sentinel-Timestamp"
+)
+
+// default token bucket of a public key, in events per second and the number of events
+// that can be sent at once. Can be overridden with PUBLIC_KEY_RATE_LIMIT and PUBLIC_KEY_RATE_BURST env.
+var PUBLIC_KEY_RATE_LIMIT = 100
+var PUBLIC_KEY_RATE_BURST = 200
+
+// default token bucket of a project that does not set its own limits, a rate of 0 does not limit projects.
+// Can be overridden with PROJECT_RATE_LIMIT and PROJECT_RATE_BURST env, the burst defaults to the rate.
+var PROJECT_RATE_LIMIT = 0
+var PROJECT_RATE_BURST = 0
+
+// maximum rate and burst a project can set in its settings.
+var MAX_PROJECT_RATE_LIMIT = 1000
+var MAX_PROJECT_RATE_BURST = 10000
+
+// maximum number of events stored per user each calendar month, 0 is unlimited.
+// Can be overridden with MONTHLY_EVENT_QUOTA env.
+var MONTHLY_EVENT_QUOTA int64 = 0
+
+// headers describing the most restrictive rate limit of an ingestion request.
+const (
+	HEADER_RATE_LIMIT_LIMIT     = "X-RateLimit-Limit"
+	HEADER_RATE_LIMIT_REMAINING = "X-RateLimit-Remaining"
+	HEADER_RATE_LIMIT_RESET     = "X-RateLimit-Reset"
+)
+
+// header carrying a public or private API key, an alternative to the Authorization bearer token.
+const HEADER_API_KEY = "X-API-Key"
+
+// accepted window of an event's FiredAt around the time it is received, after correcting the client's clock.
+// Can be overridden with EVENT_MAX_AGE and EVENT_MAX_FUTURE env.
+var EVENT_MAX_AGE = 30 * 24 * time.Hour
+var EVENT_MAX_FUTURE = 10 * time.Minute
+
+// duration in which a client supplied event id is remembered, retries with the same
+// event id within this window are treated as duplicates. Can be overridden with EVENT_DEDUP_WINDOW env.
+var EVENT_DEDUP_WINDOW = 24 * time.Hour
+
+// lowercased User-Agent substrings used to identify crawlers, headless browsers and uptime checkers.
+// "bot" is matched at the end of a token (Googlebot/2.1, AdsBot-Google) so it does not match phone models.
+var BOT_USER_AGENT_PATTERNS = []string{
+	"bot/",
+	"bot;",
+	"bot)",
+	"bot-",
+	"crawl",
+	"spider",
+	"slurp",
+	"headlesschrome",
+	"phantomjs",
+	"lighthouse",
+	"pingdom",
+	"uptimerobot",
+	"statuscake",
+	"facebookexternalhit",
+	"bingpreview",
+}
+
+// lowercased phone brands removed from the User-Agent before matching BOT_USER_AGENT_PATTERNS,
+// their model names can end the "bot" token, e.g. (Linux; Android 9; CUBOT)
+var BOT_USER_AGENT_EXCEPTIONS = []string{
+	"cubot",
+}
+
+// published IP ranges of well known search engine crawlers.
+var BOT_IP_RANGES = []string{
+	"66.249.64.0/19",   // Googlebot
+	"157.55.39.0/24",   // Bingbot
+	"207.46.13.0/24",   // Bingbot
+	"40.77.167.0/24",   // Bingbot
+	"180.76.15.0/24",   // Baiduspider
+	"220.181.108.0/24", // Baiduspider
+}
+
+// PIXEL_GIF is a 1x1 transparent GIF returned by the tracking pixel endpoint.
+var PIXEL_GIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// referrer domains used to classify the traffic channel. entries ending with a dot
+// match any top level domain, e.g. "google." matches google.com and google.co.uk.
+var SEARCH_ENGINE_DOMAINS = []string{
+	"google.",
+	"bing.com",
+	"duckduckgo.com",
+	"yahoo.",
+	"baidu.com",
+	"yandex.",
+	"ecosia.org",
+	"search.brave.com",
+	"startpage.com",
+}
+
+var SOCIAL_DOMAINS = []string{
+	"facebook.com",
+	"fb.com",
+	"instagram.com",
+	"t.co",
+	"twitter.com",
+	"x.com",
+	"linkedin.com",
+	"lnkd.in",
+	"reddit.com",
+	"youtube.com",
+	"tiktok.com",
+	"pinterest.",
+	"threads.net",
+	"news.ycombinator.com",
+}
+
+var EMAIL_DOMAINS = []string{
+	"mail.google.com",
+	"outlook.live.com",
+	"outlook.office.com",
+	"mail.yahoo.com",
+	"mail.proton.me",
+}
+
+// utm_medium values mapped into their channel.
+var UTM_MEDIUM_CHANNELS = map[string]string{
+	"email":      "email",
+	"e-mail":     "email",
+	"newsletter": "email",
+	"social":     "social",
+	"cpc":        "paid",
+	"ppc":        "paid",
+	"paid":       "paid",
+	"display":    "paid",
+	"organic":    "search",
+	"referral":   "referral",
+}
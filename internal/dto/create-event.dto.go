@@ -1,21 +1,59 @@
 package dto
 
 type CreateEventInput struct {
+	// optional client generated id, used to deduplicate retried events
+	EventID          string `json:"EventID,omitempty" validate:"omitempty,max=100"`
 	ProjectID        string `json:"ProjectID" validate:"required,uuid"`
 	EventType        string `json:"EventType" validate:"required,max=100"`
 	EventLabel       string `json:"EventLabel,omitempty" validate:"omitempty,max=100"`
 	PageURL          string `json:"PageURL,omitempty" validate:"omitempty,url"`
+	Referrer         string `json:"Referrer,omitempty" validate:"omitempty,url"`
 	ElementPath      string `json:"ElementPath,omitempty" validate:"omitempty,max=255"`
 	ElementType      string `json:"ElementType,omitempty" validate:"omitempty,max=255"`
 	IPAddr           string `json:"-"`
 	UserAgent        string `json:"UserAgent,omitempty" validate:"omitempty,max=255"`
 	BrowserName      string `json:"BrowserName,omitempty" validate:"omitempty,max=100"`
+	BrowserVersion   string `json:"-"`
+	OSName           string `json:"-"`
+	OSVersion        string `json:"-"`
 	Country          string `json:"-"`
 	Region           string `json:"-"`
 	City             string `json:"-"`
+	IsBot            bool   `json:"-"`
 	SessionID        string `json:"SessionID,omitempty" validate:"omitempty,max=100"`
+	DistinctID       string `json:"DistinctID,omitempty" validate:"omitempty,max=255"`
+	AnonymousID      string `json:"AnonymousID,omitempty" validate:"omitempty,max=255"`
 	DeviceType       string `json:"DeviceType,omitempty" validate:"omitempty,max=100"`
 	TimeOnPage       int    `json:"TimeOnPage,omitempty"`
 	ScreenResolution string `json:"ScreenResolution,omitempty" validate:"omitempty,max=100"`
 	FiredAt          string `json:"FiredAt" validate:"required,timestamp"`
+	// time the client sent the event, used to correct FiredAt from devices with a wrong clock
+	SentAt string `json:"SentAt,omitempty" validate:"omitempty,timestamp"`
+	// FiredAt as sent by the client, set when it was corrected or clamped
+	ClientFiredAt string `json:"-"`
+	// visitor's consent to tracking, false is handled like the DNT and Sec-GPC headers
+	Consent *bool `json:"Consent,omitempty"`
+	// set when the event is stored without identifying fields because of a privacy signal
+	Anonymized bool `json:"-"`
+	// set when the request is signed with the project's signing secret
+	Trusted bool `json:"-"`
+	// number of values masked by the project's redaction rules
+	Redactions int `json:"-"`
+	// share of the events of this type kept by the project's sample rules, 1 if the type is not sampled
+	SampleRate float64 `json:"-"`
+	// arbitrary key-value pairs attached to the event, stored as JSONB
+	Properties map[string]interface{} `json:"Properties,omitempty" validate:"omitempty,max=50,dive,keys,max=100,endkeys"`
+}
+
+type CreateEventsInput struct {
+	Events []CreateEventInput `json:"Events"`
+	// time the batch was sent, used for the events without their own SentAt
+	SentAt string `json:"SentAt,omitempty"`
+}
+
+// IdentifyInput merges the anonymous visitor into a known user id.
+type IdentifyInput struct {
+	ProjectID   string `json:"ProjectID" validate:"required,uuid"`
+	AnonymousID string `json:"AnonymousID" validate:"required,max=255"`
+	DistinctID  string `json:"DistinctID" validate:"required,max=255"`
 }
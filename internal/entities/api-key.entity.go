@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type APIKey struct {
 	ID        int
@@ -10,3 +14,54 @@ type APIKey struct {
 	CreatedAt time.Time
 	ExpiredAt time.Time
 }
+
+// scopes granted to private API keys, each one is required by at least one /api/v1 route.
+const (
+	ScopeEventsRead   = "events:read"
+	ScopeEventsWrite  = "events:write"
+	ScopeProjectsRead = "projects:read"
+	ScopeExports      = "exports"
+)
+
+var APIKeyScopes = []string{ScopeEventsRead, ScopeEventsWrite, ScopeProjectsRead, ScopeExports}
+
+// actions and key types recorded in the audit trail of the keys.
+const (
+	AuditKeyCreated = "created"
+	AuditKeyRotated = "rotated"
+	AuditKeyRevoked = "revoked"
+	AuditKeyDeleted = "deleted"
+
+	KeyTypePublic  = "public"
+	KeyTypePrivate = "private"
+)
+
+// APIKeyOptions restricts a new private key. A nil ProjectID allows every project of the user
+// and a zero ExpiresIn creates a key that never expires.
+type APIKeyOptions struct {
+	Scopes    []string
+	ProjectID *uuid.UUID
+	ExpiresIn time.Duration
+}
+
+// APIError is the JSON error envelope of the API routes.
+// Error stays a plain message so clients reading it before Code existed keep working.
+type APIError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+const (
+	APIErrorMissingKey   = "missing_api_key"
+	APIErrorInvalidKey   = "invalid_api_key"
+	APIErrorBadRequest   = "bad_request"
+	APIErrorUnauthorized = "unauthorized"
+	APIErrorForbidden    = "forbidden"
+	APIErrorScope        = "insufficient_scope"
+	APIErrorNotFound     = "not_found"
+	APIErrorInternal     = "internal_error"
+	// ingestion errors
+	APIErrorInvalidSignature = "invalid_signature"
+	APIErrorRateLimited      = "rate_limited"
+	APIErrorQuotaExceeded    = "quota_exceeded"
+)
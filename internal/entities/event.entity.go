@@ -49,6 +49,7 @@ type EventTextTotal struct {
 
 type EventLastUser struct {
 	IP        net.IP
+	Name      string // distinct id, IP or visitor hash
 	Timestamp time.Time
 }
 
@@ -63,22 +64,88 @@ type EventTimestamp struct {
 }
 
 type EventDetail struct {
-	TotalEvents         int `db:"total_events"`
-	TotalEventType      int `db:"total_event_type"`
-	TotalUniqueUsers    int `db:"total_unique_users"`
-	TotalCountryVisited int `db:"total_country_visited"`
-	TotalPageURL        int `db:"total_page_url"`
-	MostVisitedURLs     []EventTextTotal
-	MostCountryVisited  []EventTextTotal
-	MostCitiesVisited   []EventTextTotal
-	MostElementsFired   []EventTextTotal
-	LastVisitedUsers    []EventLastUser
-	MostUsedBrowsers    []EventTextTotal
-	MostFiredEventType  []EventTextTotal
-	MostFiredEventLabel []EventTextTotal
+	TotalEvents          int `db:"total_events"`
+	TotalEventType       int `db:"total_event_type"`
+	TotalUniqueUsers     int `db:"total_unique_users"`
+	TotalCountryVisited  int `db:"total_country_visited"`
+	TotalPageURL         int `db:"total_page_url"`
+	MostVisitedURLs      []EventTextTotal
+	MostVisitedSources   []EventTextTotal
+	MostVisitedCampaigns []EventTextTotal
+	MostCountryVisited   []EventTextTotal
+	MostCitiesVisited    []EventTextTotal
+	MostElementsFired    []EventTextTotal
+	LastVisitedUsers     []EventLastUser
+	MostUsedBrowsers     []EventTextTotal
+	MostUsedOS           []EventTextTotal
+	MostUsedDevices      []EventTextTotal
+	MostFiredEventType   []EventTextTotal
+	MostFiredEventLabel  []EventTextTotal
+	MostUsedProperties   []EventTextTotal
+}
+
+type EventBatchResult struct {
+	Index  int
+	Status string
+	Error  string `json:"Error,omitempty"`
+}
+
+const (
+	EventAccepted    string = "accepted"
+	EventRejected    string = "rejected"
+	EventDuplicate   string = "duplicate"
+	EventDropped     string = "dropped"
+	EventSuppressed  string = "suppressed"
+	EventRateLimited string = "rate_limited"
+)
+
+// RateLimit is the state of a token bucket after taking tokens from it.
+type RateLimit struct {
+	Granted    int64         // tokens taken, less than requested when the bucket ran out
+	Limit      int64         // bucket size
+	Remaining  int64         // tokens left in the bucket
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until the next token, when less than requested were granted
 }
 
 const (
 	EventsByLastN    string = "last_100"
 	EventsByLastHour string = "last_hour"
+	EventsByTrusted  string = "trusted"
+)
+
+// UserAgent is the result of parsing the event's User-Agent string.
+type UserAgent struct {
+	BrowserName    string
+	BrowserVersion string
+	OSName         string
+	OSVersion      string
+	DeviceType     string
+}
+
+const (
+	DeviceDesktop string = "desktop"
+	DeviceMobile  string = "mobile"
+	DeviceTablet  string = "tablet"
+)
+
+// Attribution is where the visitor came from, parsed from the page URL's UTM parameters and the referrer.
+type Attribution struct {
+	UTMSource      string
+	UTMMedium      string
+	UTMCampaign    string
+	UTMTerm        string
+	UTMContent     string
+	ReferrerDomain string
+	Channel        string
+}
+
+const (
+	ChannelDirect   string = "direct"
+	ChannelSearch   string = "search"
+	ChannelSocial   string = "social"
+	ChannelEmail    string = "email"
+	ChannelPaid     string = "paid"
+	ChannelReferral string = "referral"
+	ChannelCampaign string = "campaign"
 )
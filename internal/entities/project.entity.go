@@ -1,6 +1,9 @@
 package entities
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
 
 type Project struct {
 	ID          string
@@ -11,6 +14,15 @@ type Project struct {
 	CreatedAt   time.Time
 }
 
+// APIProject is a project listed by the /api/v1/projects route, without its signing secret.
+type APIProject struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Url         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type ProjectAggr struct {
 	TotalEvents          int32
 	TotalEventTypes      int32
@@ -28,3 +40,61 @@ type ProjectAggr struct {
 	MostFiredEventLabels interface{}
 	AggregatedAtStr      string
 }
+
+const (
+	BotPolicyFlag string = "flag"
+	BotPolicyDrop string = "drop"
+)
+
+const (
+	IPModeFull     string = "full"
+	IPModeTruncate string = "truncate"
+	IPModeHash     string = "hash"
+)
+
+// URLRules are the project's rules to group page URLs into a page path.
+type URLRules struct {
+	// query parameters kept in the page path, the rest are stripped
+	QueryAllowlist []string
+	// path templates such as /product/:id, the first matching template replaces the path
+	PathPatterns []string
+}
+
+// policies for events whose FiredAt is outside of the accepted window once corrected.
+const (
+	TimestampPolicyClamp  string = "clamp"
+	TimestampPolicyReject string = "reject"
+)
+
+const (
+	PrivacySignalIgnore    string = "ignore"
+	PrivacySignalReject    string = "reject"
+	PrivacySignalAnonymize string = "anonymize"
+)
+
+const (
+	RedactEmail      string = "email"
+	RedactPhone      string = "phone"
+	RedactCreditCard string = "credit_card"
+	RedactJWT        string = "jwt"
+)
+
+// RedactDetectors are the built-in personal data detectors, each one is opt-in per project.
+var RedactDetectors = []string{RedactEmail, RedactPhone, RedactCreditCard, RedactJWT}
+
+// RedactionRules are the project's rules to mask personal data in event fields before they are stored.
+type RedactionRules struct {
+	// built-in detectors, see RedactDetectors
+	Detectors []string
+	// custom patterns of the project, every match is masked
+	Patterns []*regexp.Regexp
+}
+
+// ProjectLimits are the project's ingestion limits, zero values fall back to the server defaults.
+type ProjectLimits struct {
+	// events per second and the number of events that can be sent at once above it
+	RateLimit      int32
+	RateLimitBurst int32
+	// events stored each calendar month, 0 is unlimited
+	MonthlyQuota int64
+}
@@ -2,16 +2,28 @@ package middlewares
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/gob"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/andybalholm/brotli"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
 	"github.com/hubkudev/sentinel/configs"
 	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
 	"github.com/hubkudev/sentinel/internal/dto"
 	"github.com/hubkudev/sentinel/internal/entities"
 	"github.com/hubkudev/sentinel/internal/services"
@@ -23,6 +35,10 @@ type Middleware interface {
 	InternalRoute(c *fiber.Ctx) error
 	APIPublicRoute(c *fiber.Ctx) error
 	APIPrivateRoute(c *fiber.Ctx) error
+	RequireScope(scope string) fiber.Handler
+	BeaconBody(c *fiber.Ctx) error
+	DecompressBody(c *fiber.Ctx) error
+	ProjectCORS(c *fiber.Ctx) error
 	UnProtectedRoute(c *fiber.Ctx) error
 	LiveEventsCache(c *fiber.Ctx) error
 	LiveEventCache(c *fiber.Ctx) error
@@ -36,6 +52,7 @@ type Middleware interface {
 type MiddlewareImpl struct {
 	UserService    services.UserService
 	CacheService   services.CacheService
+	KeyService     services.KeyService
 	SessionStorage *session.Store
 }
 
@@ -43,11 +60,13 @@ func InitMiddleware(
 	userService services.UserService,
 	sessionStore *session.Store,
 	cacheService services.CacheService,
+	keyService services.KeyService,
 ) MiddlewareImpl {
 	return MiddlewareImpl{
 		UserService:    userService,
 		SessionStorage: sessionStore,
 		CacheService:   cacheService,
+		KeyService:     keyService,
 	}
 }
 
@@ -109,26 +128,28 @@ func (m *MiddlewareImpl) UnProtectedRoute(c *fiber.Ctx) error {
 }
 
 func (m *MiddlewareImpl) APIPublicRoute(c *fiber.Ctx) error {
-	var key dto.KeyPayload
-
-	if err := c.BodyParser(&key); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
-			"error": "PublicKey field is required",
-		})
+	key := headerAPIKey(c)
+
+	// the PublicKey body field is kept for clients sending the key before the headers were supported,
+	// GET requests (e.g. tracking pixel) have no body, the key is sent as a query parameter
+	if key == "" {
+		var payload dto.KeyPayload
+		if c.Method() == fiber.MethodGet {
+			payload.PublicKey = c.Query("key")
+		} else if len(c.Body()) > 0 {
+			_ = c.BodyParser(&payload)
+		}
+		key = payload.PublicKey
 	}
 
-	if key.PublicKey == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
-			"error": "PublicKey field is required",
-		})
+	if key == "" {
+		return apiError(c, fiber.StatusUnauthorized, entities.APIErrorMissingKey, "public key is required")
 	}
 
 	// check if the key is exist in the database
-	exist, err := m.UserService.FindByPublicKey(key.PublicKey)
+	exist, err := m.UserService.FindByPublicKey(key)
 	if exist == nil || err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(&fiber.Map{
-			"error": "invalid public key",
-		})
+		return apiError(c, fiber.StatusUnauthorized, entities.APIErrorInvalidKey, "invalid public key")
 	}
 
 	c.Locals("user", exist)
@@ -137,33 +158,130 @@ func (m *MiddlewareImpl) APIPublicRoute(c *fiber.Ctx) error {
 }
 
 func (m *MiddlewareImpl) APIPrivateRoute(c *fiber.Ctx) error {
-	var key dto.PrivateKeyPayload
+	key := headerAPIKey(c)
 
-	if err := c.BodyParser(&key); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
-			"error": "PrivateKey field is required",
-		})
+	// the PrivateKey body field is kept for clients sending the key before the headers were supported
+	if key == "" && len(c.Body()) > 0 {
+		var payload dto.PrivateKeyPayload
+		_ = c.BodyParser(&payload)
+		key = payload.PrivateKey
 	}
 
-	if key.PrivateKey == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(&fiber.Map{
-			"error": "PrivateKey field is required",
-		})
+	if key == "" {
+		return apiError(c, fiber.StatusUnauthorized, entities.APIErrorMissingKey, "private key is required")
 	}
 
-	// check if the key is valid in the database
-	exist, err := m.UserService.FindByPrivateKey(key.PrivateKey)
+	// check if the key is valid in the database, expired and revoked keys are not found
+	exist, err := m.UserService.FindByPrivateKey(key)
 	if exist == nil || err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(&fiber.Map{
-			"error": "invalid private key",
-		})
+		return apiError(c, fiber.StatusUnauthorized, entities.APIErrorInvalidKey, "invalid private key")
 	}
 
+	// the ip is copied before the handler returns, fiber reuses the request buffers
+	ip := strings.Clone(c.IP())
+	go func() {
+		if err := m.KeyService.MarkKeyUsed(context.Background(), exist.KeyID, ip); err != nil {
+			log.Println(err)
+		}
+	}()
+
 	c.Locals("user", exist)
 
 	return c.Next()
 }
 
+// RequireScope rejects private key requests whose key was not granted the scope.
+func (m *MiddlewareImpl) RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*gen.FindUserByPrivateKeyRow)
+		if !ok || !slices.Contains(user.Scopes, scope) {
+			return apiError(c, fiber.StatusForbidden, entities.APIErrorScope, "the key is missing the "+scope+" scope")
+		}
+		return c.Next()
+	}
+}
+
+// BeaconBody reads text/plain bodies as JSON. navigator.sendBeacon can only send
+// CORS-safelisted content types without a preflight request, which is used by the tracker.
+func (m *MiddlewareImpl) BeaconBody(c *fiber.Ctx) error {
+	if strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMETextPlain) {
+		c.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+	}
+	return c.Next()
+}
+
+// bodySizes counts requests and compressed and uncompressed body sizes per content encoding
+// on the ingestion routes, served at /misc/vars.
+var bodySizes = expvar.NewMap("ingestion_body")
+
+// DecompressBody decodes gzip, br and deflate request bodies, so mobile clients can send compressed batches.
+// Decoding stops once the body exceeds constants.MAX_DECOMPRESSED_BODY_SIZE to guard against zip bombs.
+func (m *MiddlewareImpl) DecompressBody(c *fiber.Ctx) error {
+	encoding := strings.ToLower(strings.TrimSpace(c.Get(fiber.HeaderContentEncoding)))
+	if encoding == "" {
+		encoding = "identity"
+	}
+
+	body := c.Request().Body()
+	bodySizes.Add(encoding+"_requests", 1)
+	bodySizes.Add(encoding+"_compressed_bytes", int64(len(body)))
+
+	var reader io.Reader
+	var err error
+	switch encoding {
+	case "identity":
+		bodySizes.Add(encoding+"_uncompressed_bytes", int64(len(body)))
+		return c.Next()
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case "deflate":
+		// deflate is zlib wrapped per the spec, some clients send raw deflate streams instead
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader, err = flate.NewReader(bytes.NewReader(body)), nil
+		}
+	default:
+		bodySizes.Add("rejected_unsupported", 1)
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": "unsupported content encoding " + encoding})
+	}
+	if err != nil {
+		bodySizes.Add("rejected_invalid", 1)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid " + encoding + " body"})
+	}
+
+	// read one byte past the limit to tell a body of exactly the limit from a larger one
+	decoded, err := io.ReadAll(io.LimitReader(reader, constants.MAX_DECOMPRESSED_BODY_SIZE+1))
+	if err != nil {
+		bodySizes.Add("rejected_invalid", 1)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid " + encoding + " body"})
+	}
+	if int64(len(decoded)) > constants.MAX_DECOMPRESSED_BODY_SIZE {
+		bodySizes.Add("rejected_too_large", 1)
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": fmt.Sprintf("decompressed body exceeds %d bytes", constants.MAX_DECOMPRESSED_BODY_SIZE),
+		})
+	}
+	bodySizes.Add(encoding+"_uncompressed_bytes", int64(len(decoded)))
+
+	// the header is removed so the body is not decoded again by fiber
+	c.Request().Header.Del(fiber.HeaderContentEncoding)
+	c.Request().SetBodyRaw(decoded)
+	return c.Next()
+}
+
+// ProjectCORS echoes the request origin in place of the wildcard set by the global CORS middleware,
+// so browsers can read every ingestion response, errors included. Ingestion handlers remove it
+// once the origin is checked and not allowed by the project.
+func (m *MiddlewareImpl) ProjectCORS(c *fiber.Ctx) error {
+	if origin := c.Get(fiber.HeaderOrigin); origin != "" {
+		c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		c.Vary(fiber.HeaderOrigin)
+	}
+	return c.Next()
+}
+
 func (m *MiddlewareImpl) InternalRoute(c *fiber.Ctx) error {
 	// get passphrase header
 	token := string(c.Request().Header.Peek("passphrase"))
@@ -462,6 +580,57 @@ func (m *MiddlewareImpl) JSONEventLabelCache(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// headerAPIKey returns the key of an "Authorization: Bearer <key>" or "X-API-Key: <key>" header.
+func headerAPIKey(c *fiber.Ctx) string {
+	if scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(c.Get(constants.HEADER_API_KEY))
+}
+
+func apiError(c *fiber.Ctx, status int, code string, message string) error {
+	if status == fiber.StatusUnauthorized {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	}
+	return c.Status(status).JSON(entities.APIError{Error: message, Code: code})
+}
+
+// APIErrorHandler renders the errors returned by handlers of the public API, e.g. unknown routes
+// or bodies over the size limit, with the JSON error envelope instead of fiber's plain text.
+func APIErrorHandler(c *fiber.Ctx, err error) error {
+	if !strings.HasPrefix(c.Path(), "/api/v1/") {
+		return fiber.DefaultErrorHandler(c, err)
+	}
+
+	status := fiber.StatusInternalServerError
+	message := "internal server error"
+	var e *fiber.Error
+	if errors.As(err, &e) {
+		status = e.Code
+		message = e.Message
+	} else {
+		log.Println(err)
+	}
+
+	code := entities.APIErrorInternal
+	switch status {
+	case fiber.StatusBadRequest:
+		code = entities.APIErrorBadRequest
+	case fiber.StatusUnauthorized:
+		code = entities.APIErrorUnauthorized
+	case fiber.StatusForbidden:
+		code = entities.APIErrorForbidden
+	case fiber.StatusNotFound:
+		code = entities.APIErrorNotFound
+	default:
+		if status < fiber.StatusInternalServerError {
+			code = strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+		}
+	}
+
+	return apiError(c, status, code, message)
+}
+
 func redirectTo(c *fiber.Ctx, path string) error {
 	htmx := c.Get("HX-Request")
 
@@ -0,0 +1,530 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andybalholm/brotli"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/storage/redis/v2"
+	"github.com/google/uuid"
+	"github.com/hubkudev/sentinel/configs"
+	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/entities"
+	"github.com/hubkudev/sentinel/internal/mocks"
+	"github.com/hubkudev/sentinel/internal/services"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testPublicKey = "public-key"
+
+type ingestionTest struct {
+	app         *fiber.App
+	userRepo    *mocks.UserRepo
+	projectRepo *mocks.ProjectRepo
+	eventRepo   *mocks.EventRepo
+	redis       *miniredis.Miniredis
+	userID      uuid.UUID
+}
+
+// initIngestionTest routes POST /api/v1/event like the api routes, with mocked repositories and an in-memory redis.
+func initIngestionTest(t *testing.T) *ingestionTest {
+	validate := validator.New()
+	_ = validate.RegisterValidation("timestamp", constants.IsISO8601Date)
+
+	test := &ingestionTest{
+		userRepo:    mocks.NewUserRepo(t),
+		projectRepo: mocks.NewProjectRepo(t),
+		eventRepo:   mocks.NewEventRepo(t),
+		redis:       miniredis.RunT(t),
+		userID:      uuid.New(),
+	}
+
+	utilService := services.InitUtilService(validate, mocks.NewIPDBRepo(t))
+	cacheService := services.InitCacheService(redis.New(redis.Config{Addrs: []string{test.redis.Addr()}}))
+	userService := services.InitUserService(&utilService, test.userRepo)
+	eventService := services.InitEventService(&utilService, &cacheService, nil, *services.InitWorkerPool(1, 100), services.InitProcessorChain(), test.eventRepo, test.projectRepo)
+	m := InitMiddleware(&userService, nil, &cacheService, nil)
+
+	test.userRepo.On("FindUserByPublicKey", mock.Anything, testPublicKey).Return(gen.FindUserByPublicKeyRow{ID: test.userID}, nil).Maybe()
+	test.eventRepo.On("CountUserMonthlyEvents", mock.Anything, test.userID).Return(int64(0), nil).Maybe()
+
+	test.app = fiber.New(fiber.Config{ErrorHandler: APIErrorHandler})
+	test.app.Use(cors.New())
+	test.app.Post("/api/v1/event", m.ProjectCORS, m.APIPublicRoute, eventService.CreateEvent)
+	return test
+}
+
+// project registers the settings of a project owned by the test user.
+func (e *ingestionTest) project(settings gen.FindProjectSettingsRow) uuid.UUID {
+	settings.ID = uuid.New()
+	e.projectRepo.On("FindSettings", mock.Anything, &gen.FindProjectSettingsParams{
+		ID:     settings.ID,
+		UserID: e.userID,
+	}).Return(settings, nil).Maybe()
+	return settings.ID
+}
+
+func (e *ingestionTest) send(t *testing.T, origin string, key string, body string) (int, string) {
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/event", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderOrigin, origin)
+	if key != "" {
+		req.Header.Set(constants.HEADER_API_KEY, key)
+	}
+
+	res, err := e.app.Test(req)
+	assert.NoError(t, err)
+	return res.StatusCode, res.Header.Get(fiber.HeaderAccessControlAllowOrigin)
+}
+
+func eventBody(projectID uuid.UUID) string {
+	return `{"ProjectID":"` + projectID.String() + `","EventType":"click","FiredAt":"` + time.Now().Format(time.RFC3339) + `"}`
+}
+
+func TestProjectCORS(t *testing.T) {
+	const origin = "https://shop.example.com"
+
+	t.Run("Should allow any origin for projects without url or allowed origins", func(t *testing.T) {
+		test := initIngestionTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		status, allowOrigin := test.send(t, origin, testPublicKey, eventBody(projectID))
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, origin, allowOrigin)
+	})
+
+	t.Run("Should allow the origins of a restricted project", func(t *testing.T) {
+		test := initIngestionTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{
+			Url:            pgtype.Text{String: "https://example.com", Valid: true},
+			AllowedOrigins: []string{"*.example.com"},
+		})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		status, allowOrigin := test.send(t, origin, testPublicKey, eventBody(projectID))
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, origin, allowOrigin)
+	})
+
+	t.Run("Should not allow other origins of a restricted project", func(t *testing.T) {
+		test := initIngestionTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{
+			Url: pgtype.Text{String: "https://example.com", Valid: true},
+		})
+
+		status, allowOrigin := test.send(t, "https://evil.test", testPublicKey, eventBody(projectID))
+
+		assert.Equal(t, fiber.StatusForbidden, status)
+		assert.Empty(t, allowOrigin)
+	})
+
+	t.Run("Should allow the origin to read a missing key error", func(t *testing.T) {
+		test := initIngestionTest(t)
+
+		status, allowOrigin := test.send(t, origin, "", eventBody(uuid.New()))
+
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		assert.Equal(t, origin, allowOrigin)
+	})
+
+	t.Run("Should allow the origin to read a validation error", func(t *testing.T) {
+		test := initIngestionTest(t)
+
+		status, allowOrigin := test.send(t, origin, testPublicKey, `{"ProjectID":"`+uuid.NewString()+`"}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Equal(t, origin, allowOrigin)
+	})
+
+	t.Run("Should allow the origin to read a rate limit error", func(t *testing.T) {
+		test := initIngestionTest(t)
+		// an empty bucket that is not refilled before the request
+		test.redis.HSet(configs.CACHE_RATE_LIMIT_KEY(test.userID), "tokens", "0", "ts", "99999999999999")
+
+		status, allowOrigin := test.send(t, origin, testPublicKey, eventBody(uuid.New()))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+		assert.Equal(t, origin, allowOrigin)
+	})
+}
+
+func compress(t *testing.T, encoding string, body []byte) []byte {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "br":
+		writer = brotli.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		var err error
+		writer, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		assert.NoError(t, err)
+	default:
+		return body
+	}
+	_, err := writer.Write(body)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestDecompressBody(t *testing.T) {
+	payload := []byte(`{"EventType":"click"}`)
+
+	tests := []struct {
+		name           string
+		encoding       string
+		body           []byte
+		expectedStatus int
+		expectedBody   []byte
+	}{
+		{
+			name:           "Should pass uncompressed bodies through",
+			encoding:       "",
+			body:           payload,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   payload,
+		},
+		{
+			name:           "Should decode gzip bodies",
+			encoding:       "gzip",
+			body:           compress(t, "gzip", payload),
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   payload,
+		},
+		{
+			name:           "Should decode br bodies",
+			encoding:       "br",
+			body:           compress(t, "br", payload),
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   payload,
+		},
+		{
+			name:           "Should decode zlib wrapped deflate bodies",
+			encoding:       "deflate",
+			body:           compress(t, "deflate", payload),
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   payload,
+		},
+		{
+			name:           "Should decode raw deflate bodies",
+			encoding:       "deflate",
+			body:           compress(t, "raw-deflate", payload),
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   payload,
+		},
+		{
+			name:           "Should accept a body of exactly the decompressed limit",
+			encoding:       "gzip",
+			body:           compress(t, "gzip", make([]byte, constants.MAX_DECOMPRESSED_BODY_SIZE)),
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Should reject gzip bodies over the decompressed limit",
+			encoding:       "gzip",
+			body:           compress(t, "gzip", make([]byte, constants.MAX_DECOMPRESSED_BODY_SIZE+1)),
+			expectedStatus: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Should reject br bodies over the decompressed limit",
+			encoding:       "br",
+			body:           compress(t, "br", make([]byte, constants.MAX_DECOMPRESSED_BODY_SIZE+1)),
+			expectedStatus: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Should reject deflate bodies over the decompressed limit",
+			encoding:       "deflate",
+			body:           compress(t, "deflate", make([]byte, constants.MAX_DECOMPRESSED_BODY_SIZE+1)),
+			expectedStatus: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Should reject invalid gzip bodies",
+			encoding:       "gzip",
+			body:           payload,
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Should reject unsupported encodings",
+			encoding:       "compress",
+			body:           payload,
+			expectedStatus: fiber.StatusUnsupportedMediaType,
+		},
+	}
+
+	m := InitMiddleware(nil, nil, nil, nil)
+	app := fiber.New()
+	app.Post("/", m.DecompressBody, func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(test.body))
+			if test.encoding != "" {
+				req.Header.Set(fiber.HeaderContentEncoding, test.encoding)
+			}
+
+			res, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+
+			body, _ := io.ReadAll(res.Body)
+			if test.expectedBody != nil {
+				assert.Equal(t, test.expectedBody, body)
+			} else if test.expectedStatus == fiber.StatusOK {
+				assert.Len(t, body, int(constants.MAX_DECOMPRESSED_BODY_SIZE))
+			}
+		})
+	}
+}
+
+const testPrivateKey = "snt_AbCd1234-private-key"
+
+type privateAPITest struct {
+	app         *fiber.App
+	userRepo    *mocks.UserRepo
+	eventRepo   *mocks.EventRepo
+	projectRepo *mocks.ProjectRepo
+	util        services.UtilServiceImpl
+	userID      uuid.UUID
+}
+
+// initPrivateAPITest routes the private key routes like the api routes, with mocked repositories.
+func initPrivateAPITest(t *testing.T) *privateAPITest {
+	validate := validator.New()
+	_ = validate.RegisterValidation("timestamp", constants.IsISO8601Date)
+
+	test := &privateAPITest{
+		userRepo:    mocks.NewUserRepo(t),
+		eventRepo:   mocks.NewEventRepo(t),
+		projectRepo: mocks.NewProjectRepo(t),
+		util:        services.InitUtilService(validate, mocks.NewIPDBRepo(t)),
+		userID:      uuid.New(),
+	}
+
+	keyRepo := mocks.NewKeyRepo(t)
+	keyRepo.On("UpdateLastUsed", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	userService := services.InitUserService(&test.util, test.userRepo)
+	keyService := services.InitKeyService(&test.util, keyRepo)
+	eventService := services.InitEventService(&test.util, nil, nil, *services.InitWorkerPool(1, 100), services.InitProcessorChain(), test.eventRepo, test.projectRepo)
+	projectService := services.InitProjectService(test.projectRepo, &eventService, &test.util)
+	downloadService := services.InitDownloadService(&test.util, mocks.NewDownloadRepo(t))
+	apiService := services.InitAPIService(&projectService, &eventService, &downloadService, nil, &keyService, nil)
+	m := InitMiddleware(&userService, nil, nil, &keyService)
+
+	test.app = fiber.New(fiber.Config{ErrorHandler: APIErrorHandler})
+	v1 := test.app.Group("/api/v1")
+	v1.Get("/events", m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsRead), eventService.GetEvents)
+	v1.Get("/events/properties/:key", m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsRead), eventService.GetEventPropertyBreakdown)
+	v1.Get("/events/export", m.APIPrivateRoute, m.RequireScope(entities.ScopeExports), apiService.ExportEvents)
+	v1.Get("/projects", m.APIPrivateRoute, m.RequireScope(entities.ScopeProjectsRead), apiService.GetProjects)
+	v1.Post("/server/event", m.DecompressBody, m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsWrite), eventService.CreateEvent)
+	v1.Post("/server/events", m.DecompressBody, m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsWrite), eventService.CreateEvents)
+	return test
+}
+
+// key returns the row of the test private key as found by its prefix.
+func (e *privateAPITest) key(scopes []string, projectID uuid.UUID) gen.FindUserByPrivateKeyRow {
+	return gen.FindUserByPrivateKeyRow{
+		ID:        e.userID,
+		KeyID:     1,
+		Scopes:    scopes,
+		ProjectID: pgtype.UUID{Bytes: projectID, Valid: projectID != uuid.Nil},
+		TokenSalt: "salt",
+		TokenHash: e.util.HashToken(testPrivateKey, "salt"),
+	}
+}
+
+func TestAPIPrivateRoute(t *testing.T) {
+	keyProjectID := uuid.New()
+
+	tests := []struct {
+		name           string
+		key            string
+		query          string
+		candidates     func(e *privateAPITest) []gen.FindUserByPrivateKeyRow
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "Should allow a key with the scope of the route",
+			key:  testPrivateKey,
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				return []gen.FindUserByPrivateKeyRow{e.key([]string{entities.ScopeEventsRead}, uuid.Nil)}
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "Should reject requests without key",
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedCode:   entities.APIErrorMissingKey,
+		},
+		{
+			name: "Should reject a key of another token with the same prefix",
+			key:  testPrivateKey[:constants.API_KEY_PREFIX_LENGTH] + "-another-key",
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				return []gen.FindUserByPrivateKeyRow{e.key([]string{entities.ScopeEventsRead}, uuid.Nil)}
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedCode:   entities.APIErrorInvalidKey,
+		},
+		{
+			name: "Should reject an expired key",
+			key:  testPrivateKey,
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				key := e.key([]string{entities.ScopeEventsRead}, uuid.Nil)
+				key.ExpiredAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+				return []gen.FindUserByPrivateKeyRow{key}
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedCode:   entities.APIErrorInvalidKey,
+		},
+		{
+			name: "Should reject a revoked key",
+			key:  testPrivateKey,
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				key := e.key([]string{entities.ScopeEventsRead}, uuid.Nil)
+				key.RevokedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+				return []gen.FindUserByPrivateKeyRow{key}
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedCode:   entities.APIErrorInvalidKey,
+		},
+		{
+			name: "Should reject a key missing the scope of the route",
+			key:  testPrivateKey,
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				return []gen.FindUserByPrivateKeyRow{e.key([]string{}, uuid.Nil)}
+			},
+			expectedStatus: fiber.StatusForbidden,
+			expectedCode:   entities.APIErrorScope,
+		},
+		{
+			name:  "Should reject another project than the one of the key",
+			key:   testPrivateKey,
+			query: "?project_id=" + uuid.NewString(),
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				return []gen.FindUserByPrivateKeyRow{e.key([]string{entities.ScopeEventsRead}, keyProjectID)}
+			},
+			expectedStatus: fiber.StatusForbidden,
+			expectedCode:   entities.APIErrorForbidden,
+		},
+		{
+			name:  "Should allow the project of the key",
+			key:   testPrivateKey,
+			query: "?project_id=" + keyProjectID.String(),
+			candidates: func(e *privateAPITest) []gen.FindUserByPrivateKeyRow {
+				return []gen.FindUserByPrivateKeyRow{e.key([]string{entities.ScopeEventsRead}, keyProjectID)}
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initPrivateAPITest(t)
+			if test.candidates != nil {
+				e.userRepo.On("FindUserByPrivateKey", mock.Anything, testPrivateKey[:constants.API_KEY_PREFIX_LENGTH]).Return(test.candidates(e), nil).Once()
+			}
+			if test.expectedStatus == fiber.StatusOK {
+				e.eventRepo.On("GetEvents", mock.Anything, mock.MatchedBy(func(params *gen.GetEventsParams) bool {
+					return params.UserID == e.userID && (test.query == "" || params.ProjectID == keyProjectID)
+				})).Return([]gen.GetEventsRow{}, nil).Once()
+			}
+
+			req := httptest.NewRequest(fiber.MethodGet, "/api/v1/events"+test.query, nil)
+			if test.key != "" {
+				req.Header.Set(fiber.HeaderAuthorization, "Bearer "+test.key)
+			}
+
+			res, err := e.app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+
+			if test.expectedCode != "" {
+				var apiErr entities.APIError
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&apiErr))
+				assert.Equal(t, test.expectedCode, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	// requests are invalid past the scope check so handlers stop before reading the database
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		scope  string
+	}{
+		{method: fiber.MethodGet, path: "/api/v1/events?limit=0", scope: entities.ScopeEventsRead},
+		{method: fiber.MethodGet, path: "/api/v1/events/properties/plan?interval=last_decade", scope: entities.ScopeEventsRead},
+		{method: fiber.MethodGet, path: "/api/v1/events/export", scope: entities.ScopeExports},
+		{method: fiber.MethodGet, path: "/api/v1/projects", scope: entities.ScopeProjectsRead},
+		{method: fiber.MethodPost, path: "/api/v1/server/event", body: `{}`, scope: entities.ScopeEventsWrite},
+		{method: fiber.MethodPost, path: "/api/v1/server/events", body: `{"Events":[]}`, scope: entities.ScopeEventsWrite},
+	}
+
+	for _, test := range tests {
+		others := []string{}
+		for _, scope := range entities.APIKeyScopes {
+			if scope != test.scope {
+				others = append(others, scope)
+			}
+		}
+
+		send := func(t *testing.T, scopes []string) {
+			e := initPrivateAPITest(t)
+			e.userRepo.On("FindUserByPrivateKey", mock.Anything, testPrivateKey[:constants.API_KEY_PREFIX_LENGTH]).Return([]gen.FindUserByPrivateKeyRow{e.key(scopes, uuid.Nil)}, nil).Once()
+			e.projectRepo.On("FindAll", mock.Anything, e.userID).Return([]gen.FindAllProjectsRow{}, nil).Maybe()
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testPrivateKey)
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			res, err := e.app.Test(req)
+			assert.NoError(t, err)
+
+			var apiErr entities.APIError
+			_ = json.NewDecoder(res.Body).Decode(&apiErr)
+			if slices.Contains(scopes, test.scope) {
+				assert.NotEqual(t, fiber.StatusForbidden, res.StatusCode)
+				assert.NotEqual(t, entities.APIErrorScope, apiErr.Code)
+			} else {
+				assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+				assert.Equal(t, entities.APIErrorScope, apiErr.Code)
+			}
+		}
+
+		t.Run("Should require "+test.scope+" for "+test.method+" "+test.path, func(t *testing.T) {
+			send(t, others)
+		})
+		t.Run("Should allow "+test.scope+" for "+test.method+" "+test.path, func(t *testing.T) {
+			send(t, []string{test.scope})
+		})
+	}
+}
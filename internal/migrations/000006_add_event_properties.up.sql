@@ -0,0 +1,3 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS properties JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_events_properties ON events USING GIN (properties);
@@ -0,0 +1,3 @@
+ALTER TABLE events DROP COLUMN IF EXISTS os_version;
+ALTER TABLE events DROP COLUMN IF EXISTS os_name;
+ALTER TABLE events DROP COLUMN IF EXISTS browser_version;
@@ -0,0 +1,3 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS browser_version VARCHAR(100);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS os_name VARCHAR(100);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS os_version VARCHAR(100);
@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS bot_policy;
+
+ALTER TABLE events DROP COLUMN IF EXISTS is_bot;
@@ -0,0 +1,4 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- how bot traffic is handled on ingestion, either 'flag' (store with is_bot) or 'drop'
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS bot_policy VARCHAR(20) NOT NULL DEFAULT 'flag';
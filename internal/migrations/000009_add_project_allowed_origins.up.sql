@@ -0,0 +1,2 @@
+-- hostnames allowed to send events besides the project url, supports '*.example.com' and '*'
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS allowed_origins TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS geo_country_only;
+ALTER TABLE projects DROP COLUMN IF EXISTS ip_mode;
+
+ALTER TABLE events DROP COLUMN IF EXISTS visitor_hash;
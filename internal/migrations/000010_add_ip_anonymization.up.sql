@@ -0,0 +1,7 @@
+-- daily salted hash of the visitor, stored instead of the IP when the project hashes IPs
+ALTER TABLE events ADD COLUMN IF NOT EXISTS visitor_hash VARCHAR(64);
+
+-- how client IPs are stored, either 'full', 'truncate' (host part zeroed) or 'hash'
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS ip_mode VARCHAR(20) NOT NULL DEFAULT 'full';
+-- skip city level geolocation, only the country is stored
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS geo_country_only BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS suppressed_events;
+ALTER TABLE projects DROP COLUMN IF EXISTS privacy_signal_policy;
@@ -0,0 +1,4 @@
+-- how events with DNT, Sec-GPC or a declined consent are handled, either 'ignore', 'reject' or 'anonymize'
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS privacy_signal_policy VARCHAR(20) NOT NULL DEFAULT 'ignore';
+-- number of events rejected or anonymized because of a privacy signal
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS suppressed_events BIGINT NOT NULL DEFAULT 0;
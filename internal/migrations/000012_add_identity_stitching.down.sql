@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS identity_aliases;
+
+DROP INDEX IF EXISTS idx_events_anonymous_id;
+
+ALTER TABLE events DROP COLUMN IF EXISTS anonymous_id;
+ALTER TABLE events DROP COLUMN IF EXISTS distinct_id;
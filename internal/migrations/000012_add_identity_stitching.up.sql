@@ -0,0 +1,18 @@
+-- known user id set by the client after identify, and the anonymous id generated before it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS distinct_id VARCHAR(255);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS anonymous_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_events_anonymous_id ON events(project_id, anonymous_id);
+
+-- anonymous ids merged into a known user id, later events with the anonymous id are stored with the user id
+CREATE TABLE IF NOT EXISTS identity_aliases (
+    project_id UUID NOT NULL,
+    anonymous_id VARCHAR(255) NOT NULL,
+    distinct_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY(project_id, anonymous_id),
+    FOREIGN KEY(user_id) REFERENCES users(id),
+    FOREIGN KEY(project_id) REFERENCES projects(id)
+);
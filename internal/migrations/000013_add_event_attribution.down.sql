@@ -0,0 +1,9 @@
+ALTER TABLE events DROP COLUMN IF EXISTS utm_content;
+ALTER TABLE events DROP COLUMN IF EXISTS utm_term;
+ALTER TABLE events DROP COLUMN IF EXISTS utm_campaign;
+ALTER TABLE events DROP COLUMN IF EXISTS utm_medium;
+ALTER TABLE events DROP COLUMN IF EXISTS utm_source;
+
+ALTER TABLE events DROP COLUMN IF EXISTS channel;
+ALTER TABLE events DROP COLUMN IF EXISTS referrer_domain;
+ALTER TABLE events DROP COLUMN IF EXISTS referrer;
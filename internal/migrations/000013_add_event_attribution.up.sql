@@ -0,0 +1,10 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS referrer TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS referrer_domain VARCHAR(255);
+-- traffic channel classified on ingestion, e.g. 'direct', 'search', 'social', 'email' or 'referral'
+ALTER TABLE events ADD COLUMN IF NOT EXISTS channel VARCHAR(20);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS utm_source TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS utm_medium TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS utm_campaign TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS utm_term TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS utm_content TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS url_path_patterns;
+ALTER TABLE projects DROP COLUMN IF EXISTS url_query_allowlist;
+
+ALTER TABLE events DROP COLUMN IF EXISTS page_path;
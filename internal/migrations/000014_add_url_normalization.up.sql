@@ -0,0 +1,7 @@
+-- page url normalized with the project's url rules, aggregations group on it
+ALTER TABLE events ADD COLUMN IF NOT EXISTS page_path TEXT;
+
+-- query parameters kept in the page path, the rest are stripped
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS url_query_allowlist TEXT[] NOT NULL DEFAULT '{}';
+-- path templates such as '/product/:id' used to group dynamic routes
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS url_path_patterns TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS redacted_values;
+ALTER TABLE projects DROP COLUMN IF EXISTS redact_patterns;
+ALTER TABLE projects DROP COLUMN IF EXISTS redact_detectors;
+
+ALTER TABLE events DROP COLUMN IF EXISTS redactions;
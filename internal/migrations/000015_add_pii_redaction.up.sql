@@ -0,0 +1,9 @@
+-- number of values masked in the event by the project's redaction rules
+ALTER TABLE events ADD COLUMN IF NOT EXISTS redactions INTEGER;
+
+-- built-in detectors enabled for the project (email, phone, credit_card, jwt), none until the owner opts in
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS redact_detectors TEXT[] NOT NULL DEFAULT '{}';
+-- custom regular expressions, every match is masked
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS redact_patterns TEXT[] NOT NULL DEFAULT '{}';
+-- number of values redacted since the rules were enabled
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS redacted_values BIGINT NOT NULL DEFAULT 0;
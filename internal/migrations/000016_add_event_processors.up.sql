@@ -0,0 +1,2 @@
+-- processors run on ingestion for the project, in the order they are registered
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS processors TEXT[] NOT NULL DEFAULT '{bot_filter,geoip,user_agent,redaction}';
@@ -0,0 +1,3 @@
+ALTER TABLE events DROP COLUMN IF EXISTS trusted;
+
+ALTER TABLE projects DROP COLUMN IF EXISTS signing_secret;
@@ -0,0 +1,5 @@
+-- secret used to sign server-to-server events, NULL until generated from the project settings
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS signing_secret TEXT;
+
+-- events sent with a valid signature of the project's signing secret
+ALTER TABLE events ADD COLUMN IF NOT EXISTS trusted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_events_project_id_received_at;
+DROP INDEX IF EXISTS idx_events_user_id_received_at;
+
+ALTER TABLE projects DROP COLUMN IF EXISTS monthly_quota;
+ALTER TABLE projects DROP COLUMN IF EXISTS rate_limit_burst;
+ALTER TABLE projects DROP COLUMN IF EXISTS rate_limit;
@@ -0,0 +1,12 @@
+-- events per second accepted for the project, 0 uses the server default
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS rate_limit INTEGER NOT NULL DEFAULT 0;
+
+-- number of events the project can send at once above its rate, 0 uses the server default
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS rate_limit_burst INTEGER NOT NULL DEFAULT 0;
+
+-- maximum number of events stored for the project each calendar month, 0 is unlimited
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS monthly_quota BIGINT NOT NULL DEFAULT 0;
+
+-- monthly event counts used to seed the quota counters
+CREATE INDEX IF NOT EXISTS idx_events_user_id_received_at ON events(user_id, received_at);
+CREATE INDEX IF NOT EXISTS idx_events_project_id_received_at ON events(project_id, received_at);
@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS timestamp_policy;
+
+ALTER TABLE events DROP COLUMN IF EXISTS client_fired_at;
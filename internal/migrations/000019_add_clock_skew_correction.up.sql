@@ -0,0 +1,5 @@
+-- FiredAt sent by the client, kept when it was corrected by SentAt or clamped
+ALTER TABLE events ADD COLUMN IF NOT EXISTS client_fired_at TIMESTAMPTZ;
+
+-- policy for events whose corrected FiredAt is outside of the accepted window: clamp or reject
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS timestamp_policy VARCHAR(20) NOT NULL DEFAULT 'clamp';
@@ -0,0 +1,6 @@
+UPDATE projects SET processors = array_remove(processors, 'sampling');
+ALTER TABLE projects ALTER COLUMN processors SET DEFAULT '{bot_filter,geoip,user_agent,redaction}';
+
+ALTER TABLE projects DROP COLUMN IF EXISTS sample_rules;
+
+ALTER TABLE events DROP COLUMN IF EXISTS sample_rate;
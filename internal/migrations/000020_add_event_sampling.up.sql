@@ -0,0 +1,9 @@
+-- share of the events of the type kept by sampling, aggregations count each event as 1 / sample_rate
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1;
+
+-- sample rules such as 'scroll=0.1', the rate of the event types kept by the sampling processor
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS sample_rules TEXT[] NOT NULL DEFAULT '{}';
+
+-- the sampling processor only drops events of projects with sample rules, so it is enabled for all projects
+ALTER TABLE projects ALTER COLUMN processors SET DEFAULT '{bot_filter,sampling,geoip,user_agent,redaction}';
+UPDATE projects SET processors = array_append(processors, 'sampling') WHERE NOT 'sampling' = ANY(processors);
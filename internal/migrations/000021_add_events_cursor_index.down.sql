@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_project_id_received_at_id;
+DROP INDEX IF EXISTS idx_events_user_id_received_at_id;
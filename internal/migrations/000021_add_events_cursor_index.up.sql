@@ -0,0 +1,3 @@
+-- keyset pagination of GET /api/v1/events on (received_at, id)
+CREATE INDEX IF NOT EXISTS idx_events_user_id_received_at_id ON events(user_id, received_at, id);
+CREATE INDEX IF NOT EXISTS idx_events_project_id_received_at_id ON events(project_id, received_at, id);
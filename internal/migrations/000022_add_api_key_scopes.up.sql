@@ -0,0 +1,12 @@
+-- permissions of the key, keys created before scopes existed keep full access
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{events:read,events:write,projects:read,exports}';
+
+-- project the key is restricted to, NULL allows every project of the user
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id) ON DELETE CASCADE;
+
+-- revoked keys are rejected but kept to show their usage
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- last time and address a request was authenticated with the key
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS last_used_ip INET;
@@ -0,0 +1,7 @@
+-- plaintext tokens can't be recovered from their hashes, keys have to be created again after a rollback
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS token VARCHAR(255) UNIQUE;
+
+DROP INDEX IF EXISTS idx_api_keys_token_prefix;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS token_salt;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS token_prefix;
@@ -0,0 +1,21 @@
+-- visible start of the token, used to find the key and to tell keys apart
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS token_prefix VARCHAR(16);
+
+-- random salt and hex encoded sha256 of the salt followed by the token
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS token_salt VARCHAR(64);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+
+-- existing keys are hashed in place so they keep working, their tokens can't be shown anymore
+UPDATE api_keys SET
+    token_prefix = LEFT(token, 12),
+    token_salt = REPLACE(gen_random_uuid()::text, '-', '')
+WHERE token_hash IS NULL;
+UPDATE api_keys SET token_hash = encode(sha256(convert_to(token_salt || token, 'UTF8')), 'hex') WHERE token_hash IS NULL;
+
+ALTER TABLE api_keys ALTER COLUMN token_prefix SET NOT NULL;
+ALTER TABLE api_keys ALTER COLUMN token_salt SET NOT NULL;
+ALTER TABLE api_keys ALTER COLUMN token_hash SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_api_keys_token_prefix ON api_keys(token_prefix);
+
+-- the plaintext token is not stored anymore
+ALTER TABLE api_keys DROP COLUMN IF EXISTS token;
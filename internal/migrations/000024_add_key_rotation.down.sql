@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_api_key_audit_logs_user_id_created_at;
+DROP TABLE IF EXISTS api_key_audit_logs;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS rotated_at;
+
+DROP INDEX IF EXISTS idx_users_previous_public_key;
+DROP INDEX IF EXISTS idx_users_public_key;
+ALTER TABLE users DROP COLUMN IF EXISTS previous_public_key_expired_at;
+ALTER TABLE users DROP COLUMN IF EXISTS previous_public_key;
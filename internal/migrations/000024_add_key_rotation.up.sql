@@ -0,0 +1,28 @@
+-- public key replaced by the last rotation, still accepted until previous_public_key_expired_at
+ALTER TABLE users ADD COLUMN IF NOT EXISTS previous_public_key VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS previous_public_key_expired_at TIMESTAMPTZ;
+
+-- public keys are looked up on every ingestion request
+CREATE INDEX IF NOT EXISTS idx_users_public_key ON users(public_key);
+CREATE INDEX IF NOT EXISTS idx_users_previous_public_key ON users(previous_public_key);
+
+-- set when the key is replaced by a new one, it stays valid until its expired_at grace period ends
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- audit trail of the public and private keys of the users
+CREATE TABLE IF NOT EXISTS api_key_audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    key_type VARCHAR(16) NOT NULL,
+    key_name VARCHAR(100),
+    key_prefix VARCHAR(16) NOT NULL,
+    new_key_prefix VARCHAR(16),
+    grace_until TIMESTAMPTZ,
+    ip_addr INET,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_key_audit_logs_user_id_created_at ON api_key_audit_logs(user_id, created_at);
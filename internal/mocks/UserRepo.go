@@ -16,9 +16,34 @@ type UserRepo struct {
 	mock.Mock
 }
 
-// FindUserByPrivateKey implements repositories.UserRepo.
-func (_m *UserRepo) FindUserByPrivateKey(ctx context.Context, key string) (gen.FindUserByPrivateKeyRow, error) {
-	panic("unimplemented")
+// FindUserByPrivateKey provides a mock function with given fields: ctx, prefix
+func (_m *UserRepo) FindUserByPrivateKey(ctx context.Context, prefix string) ([]gen.FindUserByPrivateKeyRow, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByPrivateKey")
+	}
+
+	var r0 []gen.FindUserByPrivateKeyRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]gen.FindUserByPrivateKeyRow, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []gen.FindUserByPrivateKeyRow); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gen.FindUserByPrivateKeyRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // CheckAdminExist provides a mock function with given fields: ctx
@@ -2,24 +2,36 @@ package repositories
 
 import (
 	"context"
+	"log"
 
 	"github.com/google/uuid"
 	"github.com/hubkudev/sentinel/gen"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type KeyRepo interface {
 	CreateAPIKey(ctx context.Context, input *gen.CreateAPIKeyParams) (gen.CreateAPIKeyRow, error)
 	GetAllPrivateKeys(ctx context.Context, userID uuid.UUID) ([]gen.FindAllAPIKeysRow, error)
-	DeletePrivateKey(ctx context.Context, input *gen.DeleteAPIKeyParams) error
+	FindPrivateKey(ctx context.Context, input *gen.FindAPIKeyByIDParams) (gen.FindAPIKeyByIDRow, error)
+	RotatePrivateKey(ctx context.Context, input *gen.CreateAPIKeyParams, rotated *gen.RotateAPIKeyParams, audit *gen.CreateAPIKeyAuditLogParams) (gen.CreateAPIKeyRow, error)
+	FindPublicKey(ctx context.Context, userID uuid.UUID) (string, error)
+	RotatePublicKey(ctx context.Context, input *gen.RotateUserPublicKeyParams, audit *gen.CreateAPIKeyAuditLogParams) error
+	DeletePrivateKey(ctx context.Context, input *gen.DeleteAPIKeyParams) (gen.DeleteAPIKeyRow, error)
+	RevokePrivateKey(ctx context.Context, input *gen.RevokeAPIKeyParams) (gen.RevokeAPIKeyRow, error)
+	UpdateLastUsed(ctx context.Context, input *gen.UpdateAPIKeyLastUsedParams) error
+	CreateAuditLog(ctx context.Context, input *gen.CreateAPIKeyAuditLogParams) error
+	GetAuditLogs(ctx context.Context, input *gen.FindAPIKeyAuditLogsParams) ([]gen.FindAPIKeyAuditLogsRow, error)
 }
 
 type KeyRepoImpl struct {
 	Repo *gen.Queries
+	DB   *pgxpool.Pool
 }
 
-func InitKeyRepo(repo *gen.Queries) KeyRepoImpl {
+func InitKeyRepo(repo *gen.Queries, db *pgxpool.Pool) KeyRepoImpl {
 	return KeyRepoImpl{
 		Repo: repo,
+		DB:   db,
 	}
 }
 
@@ -31,6 +43,91 @@ func (r *KeyRepoImpl) GetAllPrivateKeys(ctx context.Context, userID uuid.UUID) (
 	return r.Repo.FindAllAPIKeys(ctx, userID)
 }
 
-func (r *KeyRepoImpl) DeletePrivateKey(ctx context.Context, input *gen.DeleteAPIKeyParams) error {
+func (r *KeyRepoImpl) FindPrivateKey(ctx context.Context, input *gen.FindAPIKeyByIDParams) (gen.FindAPIKeyByIDRow, error) {
+	return r.Repo.FindAPIKeyByID(ctx, *input)
+}
+
+func (r *KeyRepoImpl) RotatePrivateKey(ctx context.Context, input *gen.CreateAPIKeyParams, rotated *gen.RotateAPIKeyParams, audit *gen.CreateAPIKeyAuditLogParams) (gen.CreateAPIKeyRow, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		log.Println("error starting the transaction", err)
+		return gen.CreateAPIKeyRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.Repo.WithTx(tx)
+
+	// create the new key
+	key, err := qtx.CreateAPIKey(ctx, *input)
+	if err != nil {
+		return gen.CreateAPIKeyRow{}, err
+	}
+
+	// expire the rotated key at the end of its grace period
+	if err = qtx.RotateAPIKey(ctx, *rotated); err != nil {
+		return gen.CreateAPIKeyRow{}, err
+	}
+
+	if err = qtx.CreateAPIKeyAuditLog(ctx, *audit); err != nil {
+		return gen.CreateAPIKeyRow{}, err
+	}
+
+	// commit if everything alright
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error commiting the transaction", err)
+		return gen.CreateAPIKeyRow{}, err
+	}
+
+	return key, nil
+}
+
+func (r *KeyRepoImpl) FindPublicKey(ctx context.Context, userID uuid.UUID) (string, error) {
+	return r.Repo.FindUserPublicKey(ctx, userID)
+}
+
+func (r *KeyRepoImpl) RotatePublicKey(ctx context.Context, input *gen.RotateUserPublicKeyParams, audit *gen.CreateAPIKeyAuditLogParams) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		log.Println("error starting the transaction", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.Repo.WithTx(tx)
+
+	if err = qtx.RotateUserPublicKey(ctx, *input); err != nil {
+		return err
+	}
+
+	if err = qtx.CreateAPIKeyAuditLog(ctx, *audit); err != nil {
+		return err
+	}
+
+	// commit if everything alright
+	if err = tx.Commit(ctx); err != nil {
+		log.Println("error commiting the transaction", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *KeyRepoImpl) DeletePrivateKey(ctx context.Context, input *gen.DeleteAPIKeyParams) (gen.DeleteAPIKeyRow, error) {
 	return r.Repo.DeleteAPIKey(ctx, *input)
 }
+
+func (r *KeyRepoImpl) RevokePrivateKey(ctx context.Context, input *gen.RevokeAPIKeyParams) (gen.RevokeAPIKeyRow, error) {
+	return r.Repo.RevokeAPIKey(ctx, *input)
+}
+
+func (r *KeyRepoImpl) UpdateLastUsed(ctx context.Context, input *gen.UpdateAPIKeyLastUsedParams) error {
+	return r.Repo.UpdateAPIKeyLastUsed(ctx, *input)
+}
+
+func (r *KeyRepoImpl) CreateAuditLog(ctx context.Context, input *gen.CreateAPIKeyAuditLogParams) error {
+	return r.Repo.CreateAPIKeyAuditLog(ctx, *input)
+}
+
+func (r *KeyRepoImpl) GetAuditLogs(ctx context.Context, input *gen.FindAPIKeyAuditLogsParams) ([]gen.FindAPIKeyAuditLogsRow, error) {
+	return r.Repo.FindAPIKeyAuditLogs(ctx, *input)
+}
@@ -10,14 +10,21 @@ import (
 type EventRepo interface {
 	CheckProjectWithinUserID(ctx context.Context, input *gen.CheckProjectWithinUserIDParams) (bool, error)
 	CreateEvent(ctx context.Context, input *gen.CreateEventParams) error
+	CreateEvents(ctx context.Context, input []gen.CreateEventsParams) (int64, error)
 	GetLiveEvents(ctx context.Context, userID uuid.UUID) ([]gen.GetLiveEventsRow, error)
 	GetEvents(ctx context.Context, input *gen.GetEventsParams) ([]gen.GetEventsRow, error)
+	GetEventsAscending(ctx context.Context, input *gen.GetEventsAscendingParams) ([]gen.GetEventsAscendingRow, error)
+	GetEventPropertyBreakdown(ctx context.Context, input *gen.GetEventPropertyBreakdownParams) ([]gen.GetEventPropertyBreakdownRow, error)
 	GetLiveEventDetail(ctx context.Context, input *gen.GetLiveEventsDetailParams) ([]gen.GetLiveEventsDetailRow, error)
 	GetWeeklyEvents(ctx context.Context, input *gen.GetWeeklyEventsParams) ([]gen.GetWeeklyEventsRow, error)
 	GetWeeklyEventsTotal(ctx context.Context, input *gen.GetWeeklyEventsTotalParams) (int64, error)
 	GetPercentageEventsType(ctx context.Context, input *gen.GetPercentageEventsTypeParams) ([]gen.GetPercentageEventsTypeRow, error)
 	GetPercentageEventsLabel(ctx context.Context, input *gen.GetPercentageEventsLabelParams) ([]gen.GetPercentageEventsLabelRow, error)
 	CountUserMonthlyEvents(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountProjectMonthlyEvents(ctx context.Context, input *gen.CountProjectMonthlyEventsParams) (int64, error)
+	CreateIdentityAlias(ctx context.Context, input *gen.CreateIdentityAliasParams) error
+	FindIdentityAlias(ctx context.Context, input *gen.FindIdentityAliasParams) (string, error)
+	MergeAnonymousEvents(ctx context.Context, input *gen.MergeAnonymousEventsParams) (int64, error)
 }
 
 type EventRepoImpl struct {
@@ -38,6 +45,10 @@ func (r *EventRepoImpl) CreateEvent(ctx context.Context, input *gen.CreateEventP
 	return r.Repo.CreateEvent(ctx, *input)
 }
 
+func (r *EventRepoImpl) CreateEvents(ctx context.Context, input []gen.CreateEventsParams) (int64, error) {
+	return r.Repo.CreateEvents(ctx, input)
+}
+
 func (r *EventRepoImpl) GetLiveEvents(ctx context.Context, userID uuid.UUID) ([]gen.GetLiveEventsRow, error) {
 	return r.Repo.GetLiveEvents(ctx, userID)
 }
@@ -46,6 +57,14 @@ func (r *EventRepoImpl) GetEvents(ctx context.Context, input *gen.GetEventsParam
 	return r.Repo.GetEvents(ctx, *input)
 }
 
+func (r *EventRepoImpl) GetEventsAscending(ctx context.Context, input *gen.GetEventsAscendingParams) ([]gen.GetEventsAscendingRow, error) {
+	return r.Repo.GetEventsAscending(ctx, *input)
+}
+
+func (r *EventRepoImpl) GetEventPropertyBreakdown(ctx context.Context, input *gen.GetEventPropertyBreakdownParams) ([]gen.GetEventPropertyBreakdownRow, error) {
+	return r.Repo.GetEventPropertyBreakdown(ctx, *input)
+}
+
 func (r *EventRepoImpl) GetLiveEventDetail(ctx context.Context, input *gen.GetLiveEventsDetailParams) ([]gen.GetLiveEventsDetailRow, error) {
 	return r.Repo.GetLiveEventsDetail(ctx, *input)
 }
@@ -75,3 +94,19 @@ func (r *EventRepoImpl) GetPercentageEventsLabel(
 func (r *EventRepoImpl) CountUserMonthlyEvents(ctx context.Context, userID uuid.UUID) (int64, error) {
 	return r.Repo.CountUserMonthlyEvents(ctx, userID)
 }
+
+func (r *EventRepoImpl) CountProjectMonthlyEvents(ctx context.Context, input *gen.CountProjectMonthlyEventsParams) (int64, error) {
+	return r.Repo.CountProjectMonthlyEvents(ctx, *input)
+}
+
+func (r *EventRepoImpl) CreateIdentityAlias(ctx context.Context, input *gen.CreateIdentityAliasParams) error {
+	return r.Repo.CreateIdentityAlias(ctx, *input)
+}
+
+func (r *EventRepoImpl) FindIdentityAlias(ctx context.Context, input *gen.FindIdentityAliasParams) (string, error) {
+	return r.Repo.FindIdentityAlias(ctx, *input)
+}
+
+func (r *EventRepoImpl) MergeAnonymousEvents(ctx context.Context, input *gen.MergeAnonymousEventsParams) (int64, error) {
+	return r.Repo.MergeAnonymousEvents(ctx, *input)
+}
@@ -15,6 +15,11 @@ type ProjectRepo interface {
 	Update(ctx context.Context, input *gen.UpdateProjectParams) error
 	FindByID(ctx context.Context, input *gen.FindProjectByIDParams) (gen.FindProjectByIDRow, error)
 	FindAll(ctx context.Context, userID uuid.UUID) ([]gen.FindAllProjectsRow, error)
+	FindSettings(ctx context.Context, input *gen.FindProjectSettingsParams) (gen.FindProjectSettingsRow, error)
+	UpdateSettings(ctx context.Context, input *gen.UpdateProjectSettingsParams) error
+	UpdateSigningSecret(ctx context.Context, input *gen.UpdateProjectSigningSecretParams) error
+	IncrementSuppressed(ctx context.Context, input *gen.IncrementSuppressedEventsParams) error
+	IncrementRedacted(ctx context.Context, input *gen.IncrementRedactedValuesParams) error
 	Count(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountSize(ctx context.Context, input *gen.CountProjectSizeParams) (int64, error)
 	Delete(ctx context.Context, input *gen.DeleteProjectParams) error
@@ -52,6 +57,26 @@ func (r *ProjectRepoImpl) FindAll(ctx context.Context, userID uuid.UUID) ([]gen.
 	return r.Repo.FindAllProjects(ctx, userID)
 }
 
+func (r *ProjectRepoImpl) FindSettings(ctx context.Context, input *gen.FindProjectSettingsParams) (gen.FindProjectSettingsRow, error) {
+	return r.Repo.FindProjectSettings(ctx, *input)
+}
+
+func (r *ProjectRepoImpl) UpdateSettings(ctx context.Context, input *gen.UpdateProjectSettingsParams) error {
+	return r.Repo.UpdateProjectSettings(ctx, *input)
+}
+
+func (r *ProjectRepoImpl) UpdateSigningSecret(ctx context.Context, input *gen.UpdateProjectSigningSecretParams) error {
+	return r.Repo.UpdateProjectSigningSecret(ctx, *input)
+}
+
+func (r *ProjectRepoImpl) IncrementSuppressed(ctx context.Context, input *gen.IncrementSuppressedEventsParams) error {
+	return r.Repo.IncrementSuppressedEvents(ctx, *input)
+}
+
+func (r *ProjectRepoImpl) IncrementRedacted(ctx context.Context, input *gen.IncrementRedactedValuesParams) error {
+	return r.Repo.IncrementRedactedValues(ctx, *input)
+}
+
 func (r *ProjectRepoImpl) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
 	return r.Repo.CountProject(ctx, userID)
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/hubkudev/sentinel/internal/entities"
 	"github.com/hubkudev/sentinel/internal/middlewares"
 	"github.com/hubkudev/sentinel/internal/services"
 )
@@ -12,6 +13,8 @@ func InitAPIRoute(app *fiber.App, m middlewares.Middleware, apiService services.
 	project := api.Group("project")
 	project.Post("/create", m.ProtectedRoute, apiService.CreateProject)
 	project.Put("/update", m.ProtectedRoute, apiService.UpdateProject)
+	project.Put("/settings", m.ProtectedRoute, apiService.UpdateProjectSettings)
+	project.Put("/signing-secret", m.ProtectedRoute, apiService.RotateSigningSecret)
 	project.Delete("/delete", m.ProtectedRoute, apiService.DeleteProject)
 	project.Get("/size/:id", m.ProtectedRoute, apiService.CountProjectSize)
 	project.Get("/last-data-retrieved/:id", m.ProtectedRoute, apiService.LastDataRetrieved)
@@ -32,6 +35,9 @@ func InitAPIRoute(app *fiber.App, m middlewares.Middleware, apiService services.
 
 	key := api.Group("key")
 	key.Post("/create", m.ProtectedRoute, apiService.CreateAPIKey)
+	key.Put("/rotate", m.ProtectedRoute, apiService.RotateAPIKey)
+	key.Put("/public/rotate", m.ProtectedRoute, apiService.RotatePublicKey)
+	key.Put("/revoke", m.ProtectedRoute, apiService.RevokeAPIKey)
 	key.Delete("/delete", m.ProtectedRoute, apiService.DeleteAPIKey)
 
 	ai := api.Group("ai")
@@ -45,6 +51,17 @@ func InitAPIRoute(app *fiber.App, m middlewares.Middleware, apiService services.
 	// HERE ONWARDS ARE PUBLIC APIs RETURNED AS JSON.
 	// PUBLIC MEANS THEY ARE MEANT TO BE CONSUMED BY USER.
 	v1 := api.Group("v1")
-	v1.Post("/event", m.APIPublicRoute, eventService.CreateEvent)
-	v1.Get("/events", m.APIPrivateRoute, eventService.GetEvents)
+	v1.Post("/event", m.ProjectCORS, m.DecompressBody, m.BeaconBody, m.APIPublicRoute, eventService.CreateEvent)
+	v1.Post("/events", m.ProjectCORS, m.DecompressBody, m.BeaconBody, m.APIPublicRoute, eventService.CreateEvents)
+	v1.Post("/identify", m.ProjectCORS, m.DecompressBody, m.BeaconBody, m.APIPublicRoute, eventService.Identify)
+	v1.Get("/pixel.gif", m.APIPublicRoute, eventService.CreatePixelEvent)
+	v1.Get("/events", m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsRead), eventService.GetEvents)
+	v1.Get("/events/properties/:key", m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsRead), eventService.GetEventPropertyBreakdown)
+	v1.Get("/events/export", m.APIPrivateRoute, m.RequireScope(entities.ScopeExports), apiService.ExportEvents)
+	v1.Get("/projects", m.APIPrivateRoute, m.RequireScope(entities.ScopeProjectsRead), apiService.GetProjects)
+
+	// server-side ingestion with private keys, for backends that should not hold the public key
+	server := v1.Group("server")
+	server.Post("/event", m.DecompressBody, m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsWrite), eventService.CreateEvent)
+	server.Post("/events", m.DecompressBody, m.APIPrivateRoute, m.RequireScope(entities.ScopeEventsWrite), eventService.CreateEvents)
 }
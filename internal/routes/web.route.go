@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"expvar"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/monitor"
 	"github.com/hubkudev/sentinel/internal/middlewares"
@@ -24,4 +26,9 @@ func InitWebRoute(app *fiber.App, m middlewares.Middleware, webService services.
 	misc.Get("/tos", webService.SendTOSPage)
 	misc.Get("/auth-redirect", webService.SendAuthRedirectPage)
 	misc.Get("/metrics", monitor.New())
+	// only the ingestion body sizes, expvar.Handler would also expose the command line and memory stats
+	misc.Get("/vars", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.SendString(`{"ingestion_body": ` + expvar.Get("ingestion_body").String() + "}")
+	})
 }
@@ -1,10 +1,42 @@
 -- name: CreateAPIKey :one
 INSERT INTO 
-    api_keys(name, token, user_id, created_at, expired_at) VALUES ($1, $2, $3, $4, $5) 
-RETURNING name, token, created_at, expired_at;
+    api_keys(name, token_prefix, token_salt, token_hash, user_id, created_at, expired_at, scopes, project_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+RETURNING name, token_prefix, created_at, expired_at, scopes, project_id;
 
 -- name: FindAllAPIKeys :many
-SELECT id, name, token, created_at, expired_at FROM api_keys WHERE user_id = $1;
+SELECT k.id, k.name, k.token_prefix, k.created_at, k.expired_at, k.scopes, k.project_id, p.name AS project_name,
+    k.revoked_at, k.last_used_at, k.last_used_ip, k.rotated_at
+FROM api_keys AS k
+LEFT JOIN projects AS p ON k.project_id = p.id
+WHERE k.user_id = $1
+ORDER BY k.created_at;
 
--- name: DeleteAPIKey :exec
-DELETE FROM api_keys WHERE user_id = $1 AND id = $2;
+-- name: FindAPIKeyByID :one
+SELECT id, name, token_prefix, created_at, expired_at, scopes, project_id, revoked_at, rotated_at
+FROM api_keys WHERE user_id = $1 AND id = $2;
+
+-- name: RotateAPIKey :exec
+UPDATE api_keys SET rotated_at = NOW(), expired_at = $3 WHERE user_id = $1 AND id = $2;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys SET revoked_at = NOW() WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL
+RETURNING name, token_prefix;
+
+-- name: UpdateAPIKeyLastUsed :exec
+UPDATE api_keys SET last_used_at = NOW(), last_used_ip = $2
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: DeleteAPIKey :one
+DELETE FROM api_keys WHERE user_id = $1 AND id = $2
+RETURNING name, token_prefix;
+
+-- name: CreateAPIKeyAuditLog :exec
+INSERT INTO api_key_audit_logs(user_id, action, key_type, key_name, key_prefix, new_key_prefix, grace_until, ip_addr)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: FindAPIKeyAuditLogs :many
+SELECT action, key_type, key_name, key_prefix, new_key_prefix, grace_until, ip_addr, created_at
+FROM api_key_audit_logs WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
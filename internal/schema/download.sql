@@ -1,5 +1,5 @@
 -- name: GetEventTableHeaders :many
-SELECT column_name::text FROM information_schema.columns WHERE table_schema = 'public' AND table_name = 'events';
+SELECT column_name::text FROM information_schema.columns WHERE table_schema = 'public' AND table_name = 'events' ORDER BY ordinal_position;
 
 -- name: DownloadIntervalEventData :many
 SELECT * FROM events 
@@ -18,7 +18,28 @@ INSERT INTO events (
     fired_at,
     received_at,
     user_id,
-    project_id
+    project_id,
+    properties,
+    browser_version,
+    os_name,
+    os_version,
+    is_bot,
+    visitor_hash,
+    distinct_id,
+    anonymous_id,
+    referrer,
+    referrer_domain,
+    channel,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    utm_term,
+    utm_content,
+    page_path,
+    redactions,
+    trusted,
+    client_fired_at,
+    sample_rate
 ) VALUES (
     $1,  -- event_type
     $2,  -- event_label
@@ -38,7 +59,74 @@ INSERT INTO events (
     $16, -- fired_at
     $17, -- received_at
     $18, -- user_id
-    $19  -- project_id
+    $19, -- project_id
+    $20, -- properties
+    $21, -- browser_version
+    $22, -- os_name
+    $23, -- os_version
+    $24, -- is_bot
+    $25, -- visitor_hash
+    $26, -- distinct_id
+    $27, -- anonymous_id
+    $28, -- referrer
+    $29, -- referrer_domain
+    $30, -- channel
+    $31, -- utm_source
+    $32, -- utm_medium
+    $33, -- utm_campaign
+    $34, -- utm_term
+    $35, -- utm_content
+    $36, -- page_path
+    $37, -- redactions
+    $38, -- trusted
+    $39, -- client_fired_at
+    $40  -- sample_rate
+);
+
+-- name: CreateEvents :copyfrom
+INSERT INTO events (
+    event_type,
+    event_label,
+    page_url,
+    element_path,
+    element_type,
+    ip_addr,
+    user_agent,
+    browser_name,
+    country,
+    region,
+    city,
+    session_id,
+    device_type,
+    time_on_page,
+    screen_resolution,
+    fired_at,
+    received_at,
+    user_id,
+    project_id,
+    properties,
+    browser_version,
+    os_name,
+    os_version,
+    is_bot,
+    visitor_hash,
+    distinct_id,
+    anonymous_id,
+    referrer,
+    referrer_domain,
+    channel,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    utm_term,
+    utm_content,
+    page_path,
+    redactions,
+    trusted,
+    client_fired_at,
+    sample_rate
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40
 );
 
 -- name: GetLiveEvents :many
@@ -60,6 +148,7 @@ LIMIT 100;
 -- name: GetEvents :many
 SELECT
     p.name AS project_name,
+    e.id,
     e.event_type,
     e.event_label,
     e.page_url,
@@ -77,14 +166,130 @@ SELECT
     e.screen_resolution,
     e.fired_at,
     e.received_at,
-    e.project_id
+    e.project_id,
+    e.properties,
+    e.browser_version,
+    e.os_name,
+    e.os_version,
+    e.is_bot,
+    e.visitor_hash,
+    e.distinct_id,
+    e.anonymous_id,
+    e.referrer,
+    e.referrer_domain,
+    e.channel,
+    e.utm_source,
+    e.utm_medium,
+    e.utm_campaign,
+    e.utm_term,
+    e.utm_content,
+    e.page_path,
+    e.redactions,
+    e.trusted,
+    e.client_fired_at,
+    e.sample_rate
 FROM events AS e
 JOIN projects AS p ON e.project_id = p.id
 WHERE e.user_id = $1
 AND (@interval::int = -1 OR received_at >= NOW() - INTERVAL '1 day' * @interval::int)
 -- check if project id is provided and is not default empty UUID 
 AND (@project_id::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = @project_id) 
-ORDER BY e.received_at DESC
+-- check if properties filter is provided and is not default empty object
+AND (@properties::jsonb = '{}'::jsonb OR e.properties @> @properties::jsonb)
+-- trusted filter is optional, NULL returns both signed and unsigned events
+AND (sqlc.narg(trusted)::bool IS NULL OR e.trusted = sqlc.narg(trusted))
+-- the other filters are optional too, NULL skips them
+AND (sqlc.narg(received_from)::timestamptz IS NULL OR e.received_at >= sqlc.narg(received_from))
+AND (sqlc.narg(received_to)::timestamptz IS NULL OR e.received_at < sqlc.narg(received_to))
+AND (sqlc.narg(event_type)::text IS NULL OR e.event_type = sqlc.narg(event_type))
+AND (sqlc.narg(event_label)::text IS NULL OR e.event_label = sqlc.narg(event_label))
+AND (sqlc.narg(country)::text IS NULL OR e.country = sqlc.narg(country))
+AND (sqlc.narg(session_id)::text IS NULL OR e.session_id = sqlc.narg(session_id))
+AND (sqlc.narg(page_url_prefix)::text IS NULL OR e.page_url LIKE sqlc.narg(page_url_prefix) || '%')
+AND (sqlc.narg(device_type)::text IS NULL OR e.device_type = sqlc.narg(device_type))
+-- cursor is the (received_at, id) of the last event of the previous page
+AND (sqlc.narg(cursor_received_at)::timestamptz IS NULL OR (e.received_at, e.id) < (sqlc.narg(cursor_received_at), sqlc.narg(cursor_id)::uuid))
+ORDER BY e.received_at DESC, e.id DESC
+LIMIT COALESCE(@limit_count::integer, 100);
+
+-- name: GetEventsAscending :many
+SELECT
+    p.name AS project_name,
+    e.id,
+    e.event_type,
+    e.event_label,
+    e.page_url,
+    e.element_path,
+    e.element_type,
+    e.ip_addr,
+    e.user_agent,
+    e.browser_name,
+    e.country,
+    e.region,
+    e.city,
+    e.session_id,
+    e.device_type,
+    e.time_on_page,
+    e.screen_resolution,
+    e.fired_at,
+    e.received_at,
+    e.project_id,
+    e.properties,
+    e.browser_version,
+    e.os_name,
+    e.os_version,
+    e.is_bot,
+    e.visitor_hash,
+    e.distinct_id,
+    e.anonymous_id,
+    e.referrer,
+    e.referrer_domain,
+    e.channel,
+    e.utm_source,
+    e.utm_medium,
+    e.utm_campaign,
+    e.utm_term,
+    e.utm_content,
+    e.page_path,
+    e.redactions,
+    e.trusted,
+    e.client_fired_at,
+    e.sample_rate
+FROM events AS e
+JOIN projects AS p ON e.project_id = p.id
+WHERE e.user_id = $1
+AND (@interval::int = -1 OR received_at >= NOW() - INTERVAL '1 day' * @interval::int)
+-- check if project id is provided and is not default empty UUID 
+AND (@project_id::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = @project_id) 
+-- check if properties filter is provided and is not default empty object
+AND (@properties::jsonb = '{}'::jsonb OR e.properties @> @properties::jsonb)
+-- trusted filter is optional, NULL returns both signed and unsigned events
+AND (sqlc.narg(trusted)::bool IS NULL OR e.trusted = sqlc.narg(trusted))
+-- the other filters are optional too, NULL skips them
+AND (sqlc.narg(received_from)::timestamptz IS NULL OR e.received_at >= sqlc.narg(received_from))
+AND (sqlc.narg(received_to)::timestamptz IS NULL OR e.received_at < sqlc.narg(received_to))
+AND (sqlc.narg(event_type)::text IS NULL OR e.event_type = sqlc.narg(event_type))
+AND (sqlc.narg(event_label)::text IS NULL OR e.event_label = sqlc.narg(event_label))
+AND (sqlc.narg(country)::text IS NULL OR e.country = sqlc.narg(country))
+AND (sqlc.narg(session_id)::text IS NULL OR e.session_id = sqlc.narg(session_id))
+AND (sqlc.narg(page_url_prefix)::text IS NULL OR e.page_url LIKE sqlc.narg(page_url_prefix) || '%')
+AND (sqlc.narg(device_type)::text IS NULL OR e.device_type = sqlc.narg(device_type))
+-- cursor is the (received_at, id) of the last event of the previous page
+AND (sqlc.narg(cursor_received_at)::timestamptz IS NULL OR (e.received_at, e.id) > (sqlc.narg(cursor_received_at), sqlc.narg(cursor_id)::uuid))
+ORDER BY e.received_at ASC, e.id ASC
+LIMIT COALESCE(@limit_count::integer, 100);
+
+-- name: GetEventPropertyBreakdown :many
+SELECT
+    (e.properties ->> @property_key::text)::text AS value,
+    ROUND(SUM(1 / e.sample_rate))::bigint AS total
+FROM events AS e
+WHERE e.user_id = $1
+AND (@interval::int = -1 OR e.received_at >= NOW() - INTERVAL '1 day' * @interval::int)
+AND (@project_id::uuid = '00000000-0000-0000-0000-000000000000' OR e.project_id = @project_id) 
+AND e.properties ->> @property_key::text IS NOT NULL
+GROUP BY value
+ORDER BY total DESC
 LIMIT COALESCE(@limit_count::integer, 100);
 
 -- name: GetLiveEventsDetail :many
@@ -109,38 +314,39 @@ FROM events
 WHERE
     user_id = $2 AND project_id = $1 
     AND (@byLastHour::bool IS NOT TRUE OR received_at >= NOW() - INTERVAL '1 minute')
+    AND (@trustedOnly::bool IS NOT TRUE OR trusted = TRUE)
 ORDER BY received_at DESC
 LIMIT COALESCE(@limit_count::integer, 100);
 
 -- name: GetWeeklyEvents :many
 SELECT
   DATE_TRUNC('day', received_at)::timestamp AS timestamp,
-  COUNT(*) AS total
+  ROUND(SUM(1 / sample_rate))::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1 AND received_at >= NOW() - INTERVAL '7 days'
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE AND received_at >= NOW() - INTERVAL '7 days'
 GROUP BY timestamp ORDER BY timestamp ASC;
 
 -- name: GetWeeklyEventsTotal :one
 SELECT
-COUNT(id) AS total
+COALESCE(ROUND(SUM(1 / sample_rate)), 0)::bigint AS total
 FROM events WHERE received_at >= NOW() - INTERVAL '7 days'
-AND user_id = $2 AND project_id = $1;
+AND user_id = $2 AND project_id = $1 AND is_bot = FALSE;
 
 -- name: DeleteEventByProjectID :exec
 DELETE FROM events WHERE user_id = $1 AND project_id = $2;
 
 -- name: GetPercentageEventsType :many
-SELECT event_type, COUNT(*) AS total
+SELECT event_type, ROUND(SUM(1 / sample_rate))::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE
 GROUP BY event_type
 ORDER BY total DESC
 LIMIT 10;
 
 -- name: GetPercentageEventsLabel :many
-SELECT event_label, count(event_label) AS total
+SELECT event_label, COALESCE(ROUND(SUM(1 / sample_rate) FILTER (WHERE event_label IS NOT NULL)), 0)::bigint AS total
 FROM events
-WHERE user_id = $2 AND project_id = $1
+WHERE user_id = $2 AND project_id = $1 AND is_bot = FALSE
 GROUP BY event_label
 ORDER BY total DESC
 LIMIT 10;
@@ -149,3 +355,8 @@ LIMIT 10;
 SELECT COUNT(id) FROM events 
 WHERE user_id = $1
 AND received_at > date_trunc('month', NOW());
+
+-- name: CountProjectMonthlyEvents :one
+SELECT COUNT(id) FROM events
+WHERE project_id = $1 AND user_id = $2
+AND received_at > date_trunc('month', NOW());
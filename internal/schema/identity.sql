@@ -0,0 +1,10 @@
+-- name: CreateIdentityAlias :exec
+INSERT INTO identity_aliases(project_id, anonymous_id, distinct_id, user_id) VALUES ($1, $2, $3, $4)
+ON CONFLICT (project_id, anonymous_id) DO UPDATE SET distinct_id = EXCLUDED.distinct_id;
+
+-- name: FindIdentityAlias :one
+SELECT distinct_id FROM identity_aliases WHERE project_id = $1 AND anonymous_id = $2;
+
+-- name: MergeAnonymousEvents :execrows
+UPDATE events SET distinct_id = $3
+WHERE project_id = $1 AND anonymous_id = $2 AND user_id = $4 AND distinct_id IS NULL;
@@ -8,7 +8,7 @@ RETURNING name, description, created_at;
 UPDATE projects SET name = $1, description = $2, url = $3 WHERE id = $4 AND user_id = $5 AND deleted_at IS NULL;
 
 -- name: FindAllProjects :many
-SELECT id, name, description, url, created_at FROM projects WHERE user_id = $1 AND deleted_at IS NULL;
+SELECT id, name, description, url, created_at, bot_policy, allowed_origins, ip_mode, geo_country_only, privacy_signal_policy, url_query_allowlist, url_path_patterns, redact_detectors, redact_patterns, processors, rate_limit, rate_limit_burst, monthly_quota, timestamp_policy, sample_rules, signing_secret, suppressed_events, redacted_values FROM projects WHERE user_id = $1 AND deleted_at IS NULL;
 
 -- name: FindProjectByID :one
 SELECT id, name, description, url, created_at FROM projects WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
@@ -16,6 +16,37 @@ SELECT id, name, description, url, created_at FROM projects WHERE id = $1 AND us
 -- name: CheckProjectWithinUserID :one
 SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL);
 
+-- name: FindProjectSettings :one
+SELECT id, url, bot_policy, allowed_origins, ip_mode, geo_country_only, privacy_signal_policy, url_query_allowlist, url_path_patterns, redact_detectors, redact_patterns, processors, rate_limit, rate_limit_burst, monthly_quota, timestamp_policy, sample_rules, signing_secret FROM projects WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+
+-- name: UpdateProjectSettings :exec
+UPDATE projects SET
+    bot_policy = @bot_policy,
+    allowed_origins = @allowed_origins,
+    ip_mode = @ip_mode,
+    geo_country_only = @geo_country_only,
+    privacy_signal_policy = @privacy_signal_policy,
+    url_query_allowlist = @url_query_allowlist,
+    url_path_patterns = @url_path_patterns,
+    redact_detectors = @redact_detectors,
+    redact_patterns = @redact_patterns,
+    processors = @processors,
+    rate_limit = @rate_limit,
+    rate_limit_burst = @rate_limit_burst,
+    monthly_quota = @monthly_quota,
+    timestamp_policy = @timestamp_policy,
+    sample_rules = @sample_rules
+WHERE id = @id AND user_id = @user_id AND deleted_at IS NULL;
+
+-- name: UpdateProjectSigningSecret :exec
+UPDATE projects SET signing_secret = $3 WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL;
+
+-- name: IncrementSuppressedEvents :exec
+UPDATE projects SET suppressed_events = suppressed_events + $3 WHERE id = $1 AND user_id = $2;
+
+-- name: IncrementRedactedValues :exec
+UPDATE projects SET redacted_values = redacted_values + $3 WHERE id = $1 AND user_id = $2;
+
 -- name: CountProject :one
 SELECT COUNT(*) FROM projects WHERE user_id = $1 AND deleted_at IS NULL;
 
@@ -14,12 +14,21 @@ SELECT id, fullname, email, profile_url FROM users WHERE id = $1;
 SELECT public_key FROM users WHERE id = $1;
 
 -- name: FindUserByPublicKey :one
-SELECT id, fullname, email, profile_url FROM users WHERE public_key = $1;
-
--- name: FindUserByPrivateKey :one
-SELECT u.id, u.fullname, u.email, u.profile_url FROM users AS u
+SELECT id, fullname, email, profile_url FROM users
+WHERE public_key = $1
+OR (previous_public_key = $1 AND previous_public_key_expired_at > NOW());
+
+-- name: RotateUserPublicKey :exec
+UPDATE users SET
+    previous_public_key = public_key,
+    previous_public_key_expired_at = $2,
+    public_key = $3
+WHERE id = $1;
+
+-- name: FindUserByPrivateKey :many
+SELECT u.id, u.fullname, u.email, u.profile_url, k.id AS key_id, k.scopes, k.project_id, k.token_salt, k.token_hash, k.expired_at, k.revoked_at FROM users AS u
 JOIN api_keys AS k ON u.id = k.user_id
-WHERE k.token = $1;
+WHERE k.token_prefix = $1;
 
 -- name: CheckUserIDExist :one
 SELECT EXISTS(SELECT 1 FROM users WHERE id = $1);
@@ -78,13 +78,18 @@ func (s *AggrServiceImpl) GetDetailSummary(ctx context.Context, projectID uuid.U
 	}
 
 	var castQueryType = map[string]*[]entities.EventTextTotal{
-		"most_visited_url":     &summary.MostVisitedURLs,
-		"most_visited_country": &summary.MostCountryVisited,
-		"most_visited_city":    &summary.MostCitiesVisited,
-		"most_used_browser":    &summary.MostUsedBrowsers,
-		"most_hit_element":     &summary.MostElementsFired,
-		"most_event_type":      &summary.MostFiredEventType,
-		"most_event_label":     &summary.MostFiredEventLabel,
+		"most_visited_url":      &summary.MostVisitedURLs,
+		"most_visited_source":   &summary.MostVisitedSources,
+		"most_visited_campaign": &summary.MostVisitedCampaigns,
+		"most_visited_country":  &summary.MostCountryVisited,
+		"most_visited_city":     &summary.MostCitiesVisited,
+		"most_used_browser":     &summary.MostUsedBrowsers,
+		"most_used_os":          &summary.MostUsedOS,
+		"most_used_device":      &summary.MostUsedDevices,
+		"most_hit_element":      &summary.MostElementsFired,
+		"most_event_type":       &summary.MostFiredEventType,
+		"most_event_label":      &summary.MostFiredEventLabel,
+		"most_used_property":    &summary.MostUsedProperties,
 	}
 
 	for _, v := range sum {
@@ -93,9 +98,15 @@ func (s *AggrServiceImpl) GetDetailSummary(ctx context.Context, projectID uuid.U
 		// be converted into time.Time
 		case "last_visited_user":
 			timestamp, _ := time.Parse("2006-01-02 15:04:05.999999-07", v.Total)
-			ip, _, _ := net.ParseCIDR(v.Name.String)
+			// name is either the distinct id, the IP in CIDR notation or the visitor hash
+			name := v.Name.String
+			ip, _, err := net.ParseCIDR(name)
+			if err == nil {
+				name = ip.String()
+			}
 			summary.LastVisitedUsers = append(summary.LastVisitedUsers, entities.EventLastUser{
 				IP:        ip,
+				Name:      name,
 				Timestamp: timestamp,
 			})
 		// otherwise, just cast them directly into a map which contains
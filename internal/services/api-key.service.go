@@ -2,19 +2,32 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/entities"
 	"github.com/hubkudev/sentinel/internal/repositories"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type KeyService interface {
-	CreateAPIKey(ctx context.Context, name string, userID uuid.UUID) (*gen.CreateAPIKeyRow, error)
+	CreateAPIKey(ctx context.Context, name string, userID uuid.UUID, options *entities.APIKeyOptions, ip string) (*gen.CreateAPIKeyRow, string, error)
 	GetAllKeys(ctx context.Context, userID uuid.UUID) ([]gen.FindAllAPIKeysRow, error)
-	DeleteKey(ctx context.Context, userID uuid.UUID, keyID int) error
+	ParseScopes(scopes []string) ([]string, error)
+	ParseGracePeriod(hours string) (time.Duration, error)
+	RotateKey(ctx context.Context, userID uuid.UUID, keyID int, grace time.Duration, ip string) (*gen.CreateAPIKeyRow, string, error)
+	RotatePublicKey(ctx context.Context, userID uuid.UUID, grace time.Duration, ip string) (string, error)
+	RevokeKey(ctx context.Context, userID uuid.UUID, keyID int, ip string) error
+	MarkKeyUsed(ctx context.Context, keyID int32, ip string) error
+	DeleteKey(ctx context.Context, userID uuid.UUID, keyID int, ip string) error
+	GetAuditLogs(ctx context.Context, userID uuid.UUID) ([]gen.FindAPIKeyAuditLogsRow, error)
 }
 
 type KeyServiceImpl struct {
@@ -32,27 +45,166 @@ func InitKeyService(
 	}
 }
 
-func (s *KeyServiceImpl) CreateAPIKey(ctx context.Context, name string, userID uuid.UUID) (*gen.CreateAPIKeyRow, error) {
+// CreateAPIKey returns the new key along with its token. Only a hash of the token is stored,
+// so it can't be shown again after this.
+func (s *KeyServiceImpl) CreateAPIKey(ctx context.Context, name string, userID uuid.UUID, options *entities.APIKeyOptions, ip string) (*gen.CreateAPIKeyRow, string, error) {
+	input, token, err := s.newKey(name, userID, options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, err := s.Repo.CreateAPIKey(ctx, input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.audit(ctx, &gen.CreateAPIKeyAuditLogParams{
+		UserID:    userID,
+		Action:    entities.AuditKeyCreated,
+		KeyType:   entities.KeyTypePrivate,
+		KeyName:   pgtype.Text{String: name, Valid: true},
+		KeyPrefix: key.TokenPrefix,
+		IpAddr:    s.UtilService.ParseIP(ip),
+	})
+
+	return &key, token, nil
+}
+
+// newKey generates the token of a new private key and the hash stored in its place.
+func (s *KeyServiceImpl) newKey(name string, userID uuid.UUID, options *entities.APIKeyOptions) (*gen.CreateAPIKeyParams, string, error) {
+	scopes, err := s.ParseScopes(options.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := fmt.Sprintf("snt_%s", s.UtilService.GenerateRandomID(48))
+	salt := s.UtilService.GenerateRandomID(32)
+
 	input := gen.CreateAPIKeyParams{
-		Name:   name,
-		Token:  fmt.Sprintf("snt_%s", s.UtilService.GenerateRandomID(48)),
-		UserID: userID,
+		Name:        name,
+		TokenPrefix: token[:constants.API_KEY_PREFIX_LENGTH],
+		TokenSalt:   salt,
+		TokenHash:   s.UtilService.HashToken(token, salt),
+		UserID:      userID,
 		CreatedAt: pgtype.Timestamptz{
 			Time:  time.Now(),
 			Valid: true,
 		},
+		// keys without an expiry are stored with a NULL expired_at
 		ExpiredAt: pgtype.Timestamptz{
-			Time:  time.Now().AddDate(0, 6, 0), // 6 months from now
-			Valid: true,
+			Time:  time.Now().Add(options.ExpiresIn),
+			Valid: options.ExpiresIn > 0,
 		},
+		Scopes: scopes,
+	}
+
+	if options.ProjectID != nil {
+		input.ProjectID = pgtype.UUID{Bytes: *options.ProjectID, Valid: true}
+	}
+
+	return &input, token, nil
+}
+
+// ParseGracePeriod parses the hours a rotated key keeps working, empty uses constants.API_KEY_ROTATION_GRACE.
+func (s *KeyServiceImpl) ParseGracePeriod(hours string) (time.Duration, error) {
+	if hours == "" {
+		return constants.API_KEY_ROTATION_GRACE, nil
 	}
 
-	key, err := s.Repo.CreateAPIKey(ctx, &input)
+	value, err := strconv.Atoi(hours)
+	grace := time.Duration(value) * time.Hour
+	if err != nil || grace < 0 || grace > constants.MAX_API_KEY_ROTATION_GRACE {
+		return 0, fmt.Errorf("grace period must be between 0 and %d hours", int(constants.MAX_API_KEY_ROTATION_GRACE.Hours()))
+	}
+
+	return grace, nil
+}
+
+// RotateKey replaces an active private key with a new one with the same name, scopes, project and lifetime.
+// The rotated key keeps working for the grace period, but never past its own expiry.
+func (s *KeyServiceImpl) RotateKey(ctx context.Context, userID uuid.UUID, keyID int, grace time.Duration, ip string) (*gen.CreateAPIKeyRow, string, error) {
+	key, err := s.Repo.FindPrivateKey(ctx, &gen.FindAPIKeyByIDParams{
+		UserID: userID,
+		ID:     int32(keyID),
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", errors.New("key not found")
+	}
+
+	now := time.Now()
+	if key.RevokedAt.Valid || key.RotatedAt.Valid || (key.ExpiredAt.Valid && !key.ExpiredAt.Time.After(now)) {
+		return nil, "", errors.New("only active keys can be rotated")
+	}
+
+	options := entities.APIKeyOptions{Scopes: key.Scopes}
+	if key.ProjectID.Valid {
+		projectID := uuid.UUID(key.ProjectID.Bytes)
+		options.ProjectID = &projectID
+	}
+	if key.ExpiredAt.Valid {
+		options.ExpiresIn = key.ExpiredAt.Time.Sub(key.CreatedAt.Time)
+	}
+
+	input, token, err := s.newKey(key.Name, userID, &options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	graceUntil := now.Add(grace)
+	if key.ExpiredAt.Valid && key.ExpiredAt.Time.Before(graceUntil) {
+		graceUntil = key.ExpiredAt.Time
+	}
+
+	created, err := s.Repo.RotatePrivateKey(ctx, input, &gen.RotateAPIKeyParams{
+		UserID:    userID,
+		ID:        key.ID,
+		ExpiredAt: pgtype.Timestamptz{Time: graceUntil, Valid: true},
+	}, &gen.CreateAPIKeyAuditLogParams{
+		UserID:       userID,
+		Action:       entities.AuditKeyRotated,
+		KeyType:      entities.KeyTypePrivate,
+		KeyName:      pgtype.Text{String: key.Name, Valid: true},
+		KeyPrefix:    key.TokenPrefix,
+		NewKeyPrefix: pgtype.Text{String: input.TokenPrefix, Valid: true},
+		GraceUntil:   pgtype.Timestamptz{Time: graceUntil, Valid: true},
+		IpAddr:       s.UtilService.ParseIP(ip),
+	})
+	if err != nil {
+		return nil, "", err
 	}
 
-	return &key, nil
+	return &created, token, nil
+}
+
+// RotatePublicKey replaces the public key of the user. The previous key keeps working for the grace period,
+// so trackers can be updated without losing events. A key still in its grace period stops working.
+func (s *KeyServiceImpl) RotatePublicKey(ctx context.Context, userID uuid.UUID, grace time.Duration, ip string) (string, error) {
+	previous, err := s.Repo.FindPublicKey(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.UtilService.GenerateRandomID(48)
+	graceUntil := pgtype.Timestamptz{Time: time.Now().Add(grace), Valid: true}
+
+	err = s.Repo.RotatePublicKey(ctx, &gen.RotateUserPublicKeyParams{
+		ID:                         userID,
+		PreviousPublicKeyExpiredAt: graceUntil,
+		PublicKey:                  key,
+	}, &gen.CreateAPIKeyAuditLogParams{
+		UserID:       userID,
+		Action:       entities.AuditKeyRotated,
+		KeyType:      entities.KeyTypePublic,
+		KeyPrefix:    previous[:min(len(previous), constants.API_KEY_PREFIX_LENGTH)],
+		NewKeyPrefix: pgtype.Text{String: key[:constants.API_KEY_PREFIX_LENGTH], Valid: true},
+		GraceUntil:   graceUntil,
+		IpAddr:       s.UtilService.ParseIP(ip),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
 }
 
 func (s *KeyServiceImpl) GetAllKeys(ctx context.Context, userID uuid.UUID) ([]gen.FindAllAPIKeysRow, error) {
@@ -63,13 +215,90 @@ func (s *KeyServiceImpl) GetAllKeys(ctx context.Context, userID uuid.UUID) ([]ge
 	return key, nil
 }
 
-func (s *KeyServiceImpl) DeleteKey(ctx context.Context, userID uuid.UUID, keyID int) error {
-	err := s.Repo.DeletePrivateKey(ctx, &gen.DeleteAPIKeyParams{
+// ParseScopes validates the scopes of a key, returned in the order of entities.APIKeyScopes.
+func (s *KeyServiceImpl) ParseScopes(scopes []string) ([]string, error) {
+	for _, scope := range scopes {
+		if !slices.Contains(entities.APIKeyScopes, scope) {
+			return nil, fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	var result []string
+	for _, scope := range entities.APIKeyScopes {
+		if slices.Contains(scopes, scope) {
+			result = append(result, scope)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	return result, nil
+}
+
+// RevokeKey rejects the key from now on, unlike DeleteKey its usage is still shown.
+func (s *KeyServiceImpl) RevokeKey(ctx context.Context, userID uuid.UUID, keyID int, ip string) error {
+	key, err := s.Repo.RevokePrivateKey(ctx, &gen.RevokeAPIKeyParams{
+		UserID: userID,
+		ID:     int32(keyID),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, &gen.CreateAPIKeyAuditLogParams{
+		UserID:    userID,
+		Action:    entities.AuditKeyRevoked,
+		KeyType:   entities.KeyTypePrivate,
+		KeyName:   pgtype.Text{String: key.Name, Valid: true},
+		KeyPrefix: key.TokenPrefix,
+		IpAddr:    s.UtilService.ParseIP(ip),
+	})
+
+	return nil
+}
+
+// MarkKeyUsed stores the time and address of the last request made with the key.
+// It is written at most once a minute per key, so busy keys don't write on every request.
+func (s *KeyServiceImpl) MarkKeyUsed(ctx context.Context, keyID int32, ip string) error {
+	return s.Repo.UpdateLastUsed(ctx, &gen.UpdateAPIKeyLastUsedParams{
+		ID:         keyID,
+		LastUsedIp: s.UtilService.ParseIP(ip),
+	})
+}
+
+func (s *KeyServiceImpl) DeleteKey(ctx context.Context, userID uuid.UUID, keyID int, ip string) error {
+	key, err := s.Repo.DeletePrivateKey(ctx, &gen.DeleteAPIKeyParams{
 		UserID: userID,
 		ID:     int32(keyID),
 	})
 	if err != nil {
 		return err
 	}
+
+	s.audit(ctx, &gen.CreateAPIKeyAuditLogParams{
+		UserID:    userID,
+		Action:    entities.AuditKeyDeleted,
+		KeyType:   entities.KeyTypePrivate,
+		KeyName:   pgtype.Text{String: key.Name, Valid: true},
+		KeyPrefix: key.TokenPrefix,
+		IpAddr:    s.UtilService.ParseIP(ip),
+	})
+
 	return nil
 }
+
+func (s *KeyServiceImpl) GetAuditLogs(ctx context.Context, userID uuid.UUID) ([]gen.FindAPIKeyAuditLogsRow, error) {
+	return s.Repo.GetAuditLogs(ctx, &gen.FindAPIKeyAuditLogsParams{
+		UserID: userID,
+		Limit:  constants.API_KEY_AUDIT_LOG_LIMIT,
+	})
+}
+
+// audit records a change of a key, the change itself is kept when the audit trail can't be written.
+func (s *KeyServiceImpl) audit(ctx context.Context, input *gen.CreateAPIKeyAuditLogParams) {
+	if err := s.Repo.CreateAuditLog(ctx, input); err != nil {
+		log.Println("error writing the key audit log", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -25,6 +27,8 @@ import (
 type APIService interface {
 	CreateProject(ctx *fiber.Ctx) error
 	UpdateProject(ctx *fiber.Ctx) error
+	UpdateProjectSettings(ctx *fiber.Ctx) error
+	RotateSigningSecret(ctx *fiber.Ctx) error
 	DeleteProject(ctx *fiber.Ctx) error
 	CountProjectSize(ctx *fiber.Ctx) error
 	CountMonthlyEvents(ctx *fiber.Ctx) error
@@ -39,9 +43,14 @@ type APIService interface {
 	JSONEventTypeChart(c *fiber.Ctx) error
 	JSONEventLabelChart(c *fiber.Ctx) error
 	CreateAPIKey(ctx *fiber.Ctx) error
+	RotateAPIKey(ctx *fiber.Ctx) error
+	RotatePublicKey(ctx *fiber.Ctx) error
+	RevokeAPIKey(ctx *fiber.Ctx) error
 	DeleteAPIKey(ctx *fiber.Ctx) error
 	GetProjectSummary(c *fiber.Ctx) error
 	StreamAgentSummary(c *fiber.Ctx) error
+	GetProjects(c *fiber.Ctx) error
+	ExportEvents(c *fiber.Ctx) error
 }
 
 type APIServiceImpl struct {
@@ -152,6 +161,118 @@ func (s *APIServiceImpl) UpdateProject(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusOK)
 }
 
+func (s *APIServiceImpl) UpdateProjectSettings(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByIDRow)
+	projectID := c.FormValue("project_id")
+	botPolicy := c.FormValue("bot_policy", entities.BotPolicyFlag)
+
+	if botPolicy != entities.BotPolicyFlag && botPolicy != entities.BotPolicyDrop {
+		return c.SendString("Invalid bot policy")
+	}
+
+	ipMode := c.FormValue("ip_mode", entities.IPModeFull)
+	if ipMode != entities.IPModeFull && ipMode != entities.IPModeTruncate && ipMode != entities.IPModeHash {
+		return c.SendString("Invalid IP mode")
+	}
+
+	privacySignalPolicy := c.FormValue("privacy_signal_policy", entities.PrivacySignalIgnore)
+	if privacySignalPolicy != entities.PrivacySignalIgnore && privacySignalPolicy != entities.PrivacySignalReject && privacySignalPolicy != entities.PrivacySignalAnonymize {
+		return c.SendString("Invalid privacy signal policy")
+	}
+
+	timestampPolicy := c.FormValue("timestamp_policy", entities.TimestampPolicyClamp)
+	if timestampPolicy != entities.TimestampPolicyClamp && timestampPolicy != entities.TimestampPolicyReject {
+		return c.SendString("Invalid timestamp policy")
+	}
+
+	allowedOrigins, err := s.ProjectService.ParseAllowedOrigins(c.FormValue("allowed_origins"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	urlRules, err := s.ProjectService.ParseURLRules(c.FormValue("url_query_allowlist"), c.FormValue("url_path_patterns"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	// detectors are sent as one checkbox each, FormValue only returns the first one
+	var detectors []string
+	for _, detector := range c.Request().PostArgs().PeekMulti("redact_detectors") {
+		detectors = append(detectors, string(detector))
+	}
+
+	redactDetectors, redactPatterns, err := s.ProjectService.ParseRedactionRules(detectors, c.FormValue("redact_patterns"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	var processorNames []string
+	for _, name := range c.Request().PostArgs().PeekMulti("processors") {
+		processorNames = append(processorNames, string(name))
+	}
+
+	processors, err := s.ProjectService.ParseProcessors(processorNames)
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	sampleRules, err := s.ProjectService.ParseSampleRules(c.FormValue("sample_rules"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	limits, err := s.ProjectService.ParseLimits(c.FormValue("rate_limit"), c.FormValue("rate_limit_burst"), c.FormValue("monthly_quota"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	projectUUID, err := uuid.Parse(projectID)
+	if err != nil {
+		return c.SendString("Project ID required")
+	}
+
+	if err := s.ProjectService.UpdateProjectSettings(context.Background(), &gen.UpdateProjectSettingsParams{
+		BotPolicy:           botPolicy,
+		AllowedOrigins:      allowedOrigins,
+		IpMode:              ipMode,
+		GeoCountryOnly:      c.FormValue("geo_country_only") == "on",
+		PrivacySignalPolicy: privacySignalPolicy,
+		UrlQueryAllowlist:   urlRules.QueryAllowlist,
+		UrlPathPatterns:     urlRules.PathPatterns,
+		RedactDetectors:     redactDetectors,
+		RedactPatterns:      redactPatterns,
+		Processors:          processors,
+		RateLimit:           limits.RateLimit,
+		RateLimitBurst:      limits.RateLimitBurst,
+		MonthlyQuota:        limits.MonthlyQuota,
+		TimestampPolicy:     timestampPolicy,
+		SampleRules:         sampleRules,
+		ID:                  projectUUID,
+		UserID:              user.ID,
+	}); err != nil {
+		return c.Status(fiber.StatusOK).SendString(err.Error())
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (s *APIServiceImpl) RotateSigningSecret(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByIDRow)
+
+	projectUUID, err := uuid.Parse(c.FormValue("project_id"))
+	if err != nil {
+		return c.SendString("Project ID required")
+	}
+
+	if err := s.ProjectService.RotateSigningSecret(context.Background(), projectUUID, user.ID); err != nil {
+		return c.Status(fiber.StatusOK).SendString(err.Error())
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusOK)
+}
+
 func (s *APIServiceImpl) DeleteProject(c *fiber.Ctx) error {
 	user := c.Locals("user").(*gen.FindUserByIDRow)
 	projectID := c.FormValue("project_id")
@@ -377,6 +498,8 @@ func (s *APIServiceImpl) LiveEventDetail(c *fiber.Ctx) error {
 		events, err = s.EventService.GetLiveEventDetail(context.Background(), projectUUID, user.ID, entities.EventsByLastHour, 100)
 	case entities.EventsByLastN:
 		events, err = s.EventService.GetLiveEventDetail(context.Background(), projectUUID, user.ID, entities.EventsByLastN, 100)
+	case entities.EventsByTrusted:
+		events, err = s.EventService.GetLiveEventDetail(context.Background(), projectUUID, user.ID, entities.EventsByTrusted, 100)
 	}
 
 	if err != nil {
@@ -648,29 +771,109 @@ func (s *APIServiceImpl) CreateAPIKey(c *fiber.Ctx) error {
 		return c.SendString("Maximum name length is 64 characters")
 	}
 
-	_, err := s.KeyService.CreateAPIKey(context.Background(), name, user.ID)
+	options, err := s.parseKeyOptions(c, user.ID)
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	_, token, err := s.KeyService.CreateAPIKey(context.Background(), name, user.ID, options, c.IP())
 	if err != nil {
 		return c.Status(fiber.StatusOK).SendString(err.Error())
 	}
 
+	// the token is only shown in this response, the page lists the keys by prefix
+	buf := bytes.Buffer{}
+
+	text := pages.APIKeyCreatedText(token)
+	text.Render(context.Background(), &buf)
+
+	return c.SendString(buf.String())
+}
+
+// RotateAPIKey replaces a private key with a new one, the rotated key keeps working for the grace period.
+func (s *APIServiceImpl) RotateAPIKey(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByIDRow)
+
+	keyID, err := strconv.Atoi(c.FormValue("key_id"))
+	if err != nil {
+		return c.SendString("Key id is required")
+	}
+
+	grace, err := s.KeyService.ParseGracePeriod(c.FormValue("grace_hours"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	_, token, err := s.KeyService.RotateKey(context.Background(), user.ID, keyID, grace, c.IP())
+	if err != nil {
+		return c.Status(fiber.StatusOK).SendString(err.Error())
+	}
+
+	// the token is only shown in this response, the page lists the keys by prefix
+	buf := bytes.Buffer{}
+
+	text := pages.APIKeyCreatedText(token)
+	text.Render(context.Background(), &buf)
+
+	return c.SendString(buf.String())
+}
+
+// RotatePublicKey replaces the public key of the user, the previous key keeps working for the grace period.
+func (s *APIServiceImpl) RotatePublicKey(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByIDRow)
+
+	grace, err := s.KeyService.ParseGracePeriod(c.FormValue("grace_hours"))
+	if err != nil {
+		return c.SendString(err.Error())
+	}
+
+	if _, err := s.KeyService.RotatePublicKey(context.Background(), user.ID, grace, c.IP()); err != nil {
+		return c.Status(fiber.StatusOK).SendString(err.Error())
+	}
+
 	c.Set("HX-Refresh", "true")
 	return c.SendStatus(fiber.StatusOK)
 }
 
-func (s *APIServiceImpl) UpdateAPIKey(c *fiber.Ctx) error {
-	user := c.Locals("user").(*gen.FindUserByIDRow)
-	name := c.FormValue("key_name")
+// parseKeyOptions reads the scopes, project and expiry of a private key from the key form.
+func (s *APIServiceImpl) parseKeyOptions(c *fiber.Ctx, userID uuid.UUID) (*entities.APIKeyOptions, error) {
+	var options entities.APIKeyOptions
 
-	if name == "" {
-		return c.SendString("Key name is required")
+	for _, scope := range c.Request().PostArgs().PeekMulti("scopes") {
+		options.Scopes = append(options.Scopes, string(scope))
 	}
 
-	if len(name) > 64 {
-		return c.SendString("Maximum name length is 64 characters")
+	if projectID := c.FormValue("project_id"); projectID != "" {
+		projectUUID, err := uuid.Parse(projectID)
+		if err != nil {
+			return nil, errors.New("Invalid project")
+		}
+
+		// the key can only be restricted to a project of its owner
+		if _, err := s.ProjectService.GetProjectByID(context.Background(), projectUUID, userID); err != nil {
+			return nil, errors.New("Invalid project")
+		}
+		options.ProjectID = &projectUUID
+	}
+
+	days, err := strconv.Atoi(c.FormValue("expires_in", strconv.Itoa(constants.API_KEY_EXPIRY_DAYS)))
+	if err != nil || days < 0 || days > constants.MAX_API_KEY_EXPIRY_DAYS {
+		return nil, fmt.Errorf("Expiry must be between 0 and %d days", constants.MAX_API_KEY_EXPIRY_DAYS)
 	}
+	options.ExpiresIn = time.Duration(days) * 24 * time.Hour
+
+	return &options, nil
+}
 
-	_, err := s.KeyService.CreateAPIKey(context.Background(), name, user.ID)
+func (s *APIServiceImpl) RevokeAPIKey(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByIDRow)
+
+	keyID, err := strconv.Atoi(c.FormValue("key_id"))
 	if err != nil {
+		return c.SendString("Key id is required")
+	}
+
+	if err := s.KeyService.RevokeKey(context.Background(), user.ID, keyID, c.IP()); err != nil {
 		return c.Status(fiber.StatusOK).SendString(err.Error())
 	}
 
@@ -691,7 +894,7 @@ func (s *APIServiceImpl) DeleteAPIKey(c *fiber.Ctx) error {
 		return c.SendString("Key id is required")
 	}
 
-	if err := s.KeyService.DeleteKey(context.Background(), user.ID, keyID); err != nil {
+	if err := s.KeyService.DeleteKey(context.Background(), user.ID, keyID, c.IP()); err != nil {
 		return c.Status(fiber.StatusOK).SendString(err.Error())
 	}
 
@@ -817,3 +1020,90 @@ func (s *APIServiceImpl) StreamAgentSummary(c *fiber.Ctx) error {
 
 	return nil
 }
+
+// GetProjects lists the projects readable by the private key,
+// keys restricted to a project only list that project.
+func (s *APIServiceImpl) GetProjects(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByPrivateKeyRow)
+
+	rows, err := s.ProjectService.GetAllProjects(context.Background(), user.ID)
+	if err != nil {
+		log.Println("Error getting projects:", err)
+		return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "projects could not be retrieved")
+	}
+
+	projects := make([]entities.APIProject, 0, len(rows))
+	for _, row := range rows {
+		if user.ProjectID.Valid && row.ID != uuid.UUID(user.ProjectID.Bytes) {
+			continue
+		}
+
+		projects = append(projects, entities.APIProject{
+			ID:          row.ID.String(),
+			Name:        row.Name,
+			Description: row.Description.String,
+			Url:         row.Url.String,
+			CreatedAt:   row.CreatedAt.Time,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"total": len(projects),
+		"data":  projects,
+	})
+}
+
+// ExportEvents responds with the project's events of the interval as CSV,
+// the same data downloaded from the dashboard.
+func (s *APIServiceImpl) ExportEvents(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByPrivateKeyRow)
+
+	intervalQuery := c.Query("interval", "all_time")
+	interval, ok := constants.Intervals[intervalQuery]
+	if !ok {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid interval")
+	}
+
+	var projectUUID uuid.UUID
+	if projectID := c.Query("project_id"); projectID != "" {
+		var err error
+		projectUUID, err = uuid.Parse(projectID)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid project id")
+		}
+	}
+
+	projectUUID, err := keyProjectID(user, projectUUID)
+	if err != nil {
+		return apiError(c, fiber.StatusForbidden, entities.APIErrorForbidden, err.Error())
+	}
+	if projectUUID == uuid.Nil {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "project_id is required")
+	}
+
+	// check if project id is available within user context
+	exist, err := s.ProjectService.GetProjectByID(context.Background(), projectUUID, user.ID)
+	if exist == nil || err != nil {
+		return apiError(c, fiber.StatusNotFound, entities.APIErrorNotFound, errProjectNotFound.Error())
+	}
+
+	header, body, err := s.DownloadService.DownloadEventData(context.Background(), projectUUID, user.ID, int32(interval))
+	if err != nil {
+		log.Println("Error exporting events:", err)
+		return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "events could not be exported")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s_%s.csv"`, projectUUID.String(), intervalQuery))
+
+	w := csv.NewWriter(c)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if err := w.WriteAll(body); err != nil {
+		log.Println("Error writing exported events:", err)
+		return err
+	}
+
+	return nil
+}
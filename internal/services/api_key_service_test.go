@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []string
+		expectedResult []string
+		expectErr      bool
+	}{
+		{
+			name:           "Should keep valid scopes in their canonical order",
+			scopes:         []string{entities.ScopeExports, entities.ScopeEventsRead},
+			expectedResult: []string{entities.ScopeEventsRead, entities.ScopeExports},
+		},
+		{
+			name:           "Should remove duplicated scopes",
+			scopes:         []string{entities.ScopeEventsRead, entities.ScopeEventsRead},
+			expectedResult: []string{entities.ScopeEventsRead},
+		},
+		{
+			name:      "Should reject an unknown scope",
+			scopes:    []string{entities.ScopeEventsRead, "users:write"},
+			expectErr: true,
+		},
+		{
+			name:      "Should require at least one scope",
+			scopes:    nil,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keyService := KeyServiceImpl{}
+
+			result, err := keyService.ParseScopes(test.scopes)
+
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
+
+func TestParseGracePeriod(t *testing.T) {
+	tests := []struct {
+		name           string
+		hours          string
+		expectedResult time.Duration
+		expectErr      bool
+	}{
+		{
+			name:           "Should use the default grace period when empty",
+			hours:          "",
+			expectedResult: constants.API_KEY_ROTATION_GRACE,
+		},
+		{
+			name:           "Should parse the grace period in hours",
+			hours:          "72",
+			expectedResult: 72 * time.Hour,
+		},
+		{
+			name:           "Should allow stopping the rotated key right away",
+			hours:          "0",
+			expectedResult: 0,
+		},
+		{
+			name:      "Should reject a grace period over the maximum",
+			hours:     "721",
+			expectErr: true,
+		},
+		{
+			name:      "Should reject a negative grace period",
+			hours:     "-1",
+			expectErr: true,
+		},
+		{
+			name:      "Should reject an invalid grace period",
+			hours:     "a day",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keyService := KeyServiceImpl{}
+
+			result, err := keyService.ParseGracePeriod(test.hours)
+
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
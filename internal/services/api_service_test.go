@@ -0,0 +1,198 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/entities"
+	"github.com/hubkudev/sentinel/internal/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type apiTest struct {
+	service      APIServiceImpl
+	projectRepo  *mocks.ProjectRepo
+	downloadRepo *mocks.DownloadRepo
+	userID       uuid.UUID
+}
+
+// initAPITest returns an api service backed by mocked repositories.
+func initAPITest(t *testing.T) *apiTest {
+	utilService := InitUtilService(validator.New(), mocks.NewIPDBRepo(t))
+
+	test := &apiTest{
+		projectRepo:  mocks.NewProjectRepo(t),
+		downloadRepo: mocks.NewDownloadRepo(t),
+		userID:       uuid.New(),
+	}
+
+	projectService := InitProjectService(test.projectRepo, nil, &utilService)
+	downloadService := InitDownloadService(&utilService, test.downloadRepo)
+	test.service = InitAPIService(&projectService, nil, &downloadService, nil, nil, nil)
+	return test
+}
+
+// app routes the handler behind a private key user, restricted to keyProjectID when it is set.
+func (e *apiTest) app(path string, handler fiber.Handler, keyProjectID uuid.UUID) *fiber.App {
+	app := fiber.New()
+	app.Get(path, func(c *fiber.Ctx) error {
+		c.Locals("user", &gen.FindUserByPrivateKeyRow{
+			ID:        e.userID,
+			ProjectID: pgtype.UUID{Bytes: keyProjectID, Valid: keyProjectID != uuid.Nil},
+		})
+		return c.Next()
+	}, handler)
+	return app
+}
+
+func TestGetProjects(t *testing.T) {
+	first := uuid.New()
+	second := uuid.New()
+
+	tests := []struct {
+		name         string
+		keyProjectID uuid.UUID
+		expectedIDs  []string
+	}{
+		{
+			name:        "Should list every project of the user",
+			expectedIDs: []string{first.String(), second.String()},
+		},
+		{
+			name:         "Should only list the project of a restricted key",
+			keyProjectID: second,
+			expectedIDs:  []string{second.String()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initAPITest(t)
+			e.projectRepo.On("FindAll", mock.Anything, e.userID).Return([]gen.FindAllProjectsRow{
+				{ID: first, Name: "First", SigningSecret: pgtype.Text{String: "first-secret", Valid: true}},
+				{ID: second, Name: "Second", SigningSecret: pgtype.Text{String: "second-secret", Valid: true}},
+			}, nil).Once()
+
+			res, err := e.app("/projects", e.service.GetProjects, test.keyProjectID).Test(httptest.NewRequest(fiber.MethodGet, "/projects", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, res.StatusCode)
+
+			var body struct {
+				Total int                      `json:"total"`
+				Data  []map[string]interface{} `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, len(test.expectedIDs), body.Total)
+
+			ids := []string{}
+			for _, project := range body.Data {
+				ids = append(ids, project["id"].(string))
+				assert.NotContains(t, project, "SigningSecret")
+				assert.NotContains(t, project, "signing_secret")
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+
+	t.Run("Should not expose the error of a failed query", func(t *testing.T) {
+		e := initAPITest(t)
+		e.projectRepo.On("FindAll", mock.Anything, e.userID).Return(nil, errors.New("connection refused")).Once()
+
+		res, err := e.app("/projects", e.service.GetProjects, uuid.Nil).Test(httptest.NewRequest(fiber.MethodGet, "/projects", nil))
+		assert.NoError(t, err)
+
+		apiErr := decodeAPIError(t, res)
+		assert.Equal(t, fiber.StatusInternalServerError, res.StatusCode)
+		assert.Equal(t, entities.APIErrorInternal, apiErr.Code)
+		assert.NotContains(t, apiErr.Error, "connection refused")
+	})
+}
+
+func TestExportEvents(t *testing.T) {
+	projectID := uuid.New()
+
+	t.Run("Should export the events of the project as CSV", func(t *testing.T) {
+		e := initAPITest(t)
+		e.projectRepo.On("FindByID", mock.Anything, &gen.FindProjectByIDParams{ID: projectID, UserID: e.userID}).Return(gen.FindProjectByIDRow{ID: projectID}, nil).Once()
+		e.downloadRepo.On("GetEventTableHeaders", mock.Anything).Return([]string{"id", "event_type"}, nil).Once()
+		e.downloadRepo.On("DownloadIntervalData", mock.Anything, &gen.DownloadIntervalEventDataParams{
+			UserID:    e.userID,
+			ProjectID: projectID,
+			Interval:  -1,
+		}).Return([]gen.Event{{EventType: "click"}}, nil).Once()
+
+		res, err := e.app("/export", e.service.ExportEvents, projectID).Test(httptest.NewRequest(fiber.MethodGet, "/export", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/csv", res.Header.Get(fiber.HeaderContentType))
+
+		// the header is mocked, rows keep every column of the event
+		reader := csv.NewReader(res.Body)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, []string{"id", "event_type"}, records[0])
+		assert.Equal(t, "click", records[1][1])
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		keyProjectID   uuid.UUID
+		notFound       bool
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Should require a project when the key is not restricted",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject an unknown interval",
+			query:          "?interval=last_decade&project_id=" + projectID.String(),
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject another project than the one of the key",
+			query:          "?project_id=" + uuid.NewString(),
+			keyProjectID:   projectID,
+			expectedStatus: fiber.StatusForbidden,
+			expectedCode:   entities.APIErrorForbidden,
+		},
+		{
+			name:           "Should not export a project of another user",
+			query:          "?project_id=" + projectID.String(),
+			notFound:       true,
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   entities.APIErrorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initAPITest(t)
+			if test.notFound {
+				e.projectRepo.On("FindByID", mock.Anything, mock.Anything).Return(gen.FindProjectByIDRow{}, errors.New("no rows in result set")).Once()
+			}
+
+			res, err := e.app("/export", e.service.ExportEvents, test.keyProjectID).Test(httptest.NewRequest(fiber.MethodGet, "/export"+test.query, nil))
+			assert.NoError(t, err)
+
+			apiErr := decodeAPIError(t, res)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Equal(t, test.expectedCode, apiErr.Code)
+		})
+	}
+}
@@ -2,15 +2,22 @@ package services
 
 import (
 	"context"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/storage/redis/v2"
+	"github.com/hubkudev/sentinel/internal/entities"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 type CacheService interface {
 	GetCache(key string) ([]byte, error)
 	SetCache(key string, value []byte, exp time.Duration) error
+	SetCacheNX(key string, value []byte, exp time.Duration) (bool, error)
 	InvalidateCaches(keys []string) error
+	IncrementCache(key string, value int64, exp time.Duration) (int64, error)
+	TakeTokens(key string, rate float64, burst int64, cost int64) (*entities.RateLimit, error)
 }
 
 type CacheServiceImpl struct {
@@ -31,9 +38,76 @@ func (s *CacheServiceImpl) SetCache(key string, value []byte, exp time.Duration)
 	return s.RedisCon.Set(key, value, exp)
 }
 
+// SetCacheNX only sets the key if it does not exist yet,
+// returns true if the key was set.
+func (s *CacheServiceImpl) SetCacheNX(key string, value []byte, exp time.Duration) (bool, error) {
+	return s.RedisCon.Conn().SetNX(context.Background(), key, value, exp).Result()
+}
+
 func (s *CacheServiceImpl) InvalidateCaches(keys []string) error {
 	if len(keys) > 0 {
 		s.RedisCon.Conn().Unlink(context.Background(), keys...)
 	}
 	return nil
 }
+
+// IncrementCache adds the value to the counter stored in the key and returns the new value.
+// A missing key is treated as zero, the expiration is only set when the key is created.
+func (s *CacheServiceImpl) IncrementCache(key string, value int64, exp time.Duration) (int64, error) {
+	count, err := s.RedisCon.Conn().IncrBy(context.Background(), key, value).Result()
+	if err == nil && count == value {
+		err = s.RedisCon.Conn().Expire(context.Background(), key, exp).Err()
+	}
+	return count, err
+}
+
+// tokenBucketScript refills the bucket from the time elapsed since the last call,
+// then takes up to the cost from the whole tokens available.
+// It runs atomically in redis so concurrent requests can not overdraw the bucket.
+var tokenBucketScript = goredis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local time = redis.call("TIME")
+local now = time[1] * 1000 + math.floor(time[2] / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local granted = math.min(cost, math.floor(tokens))
+tokens = tokens - granted
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1000)
+return {granted, tostring(tokens)}
+`)
+
+// TakeTokens takes up to cost tokens from the bucket stored in the key.
+// The bucket holds up to burst tokens and is refilled with rate tokens per second.
+func (s *CacheServiceImpl) TakeTokens(key string, rate float64, burst int64, cost int64) (*entities.RateLimit, error) {
+	result, err := tokenBucketScript.Run(context.Background(), s.RedisCon.Conn(), []string{key}, rate, burst, cost).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	granted, _ := result[0].(int64)
+	tokens, err := strconv.ParseFloat(result[1].(string), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := &entities.RateLimit{
+		Granted:   granted,
+		Limit:     burst,
+		Remaining: int64(tokens),
+		Reset:     time.Duration((float64(burst) - tokens) / rate * float64(time.Second)),
+	}
+	if granted < cost {
+		// time until the next whole token
+		limit.RetryAfter = time.Duration((1 - (tokens - math.Floor(tokens))) / rate * float64(time.Second))
+	}
+	return limit, nil
+}
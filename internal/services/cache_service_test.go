@@ -0,0 +1,98 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/storage/redis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeTokens(t *testing.T) {
+	const key = "bucket"
+
+	tests := []struct {
+		name              string
+		tokens            float64
+		elapsed           time.Duration
+		cost              int64
+		expectedGranted   int64
+		expectedRemaining int64
+		expectRetryAfter  bool
+	}{
+		{
+			name:              "Should grant the whole cost from a new bucket",
+			tokens:            -1,
+			cost:              5,
+			expectedGranted:   5,
+			expectedRemaining: 15,
+		},
+		{
+			name:              "Should grant the tokens left when the cost is larger",
+			tokens:            3,
+			cost:              5,
+			expectedGranted:   3,
+			expectedRemaining: 0,
+			expectRetryAfter:  true,
+		},
+		{
+			name:              "Should deny requests to an empty bucket",
+			tokens:            0,
+			cost:              1,
+			expectedGranted:   0,
+			expectedRemaining: 0,
+			expectRetryAfter:  true,
+		},
+		{
+			name:              "Should refill the bucket from the elapsed time",
+			tokens:            0,
+			elapsed:           time.Second,
+			cost:              5,
+			expectedGranted:   5,
+			expectedRemaining: 5,
+		},
+		{
+			name:              "Should not refill the bucket over the burst",
+			tokens:            0,
+			elapsed:           time.Hour,
+			cost:              25,
+			expectedGranted:   20,
+			expectedRemaining: 0,
+			expectRetryAfter:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redisServer := miniredis.RunT(t)
+			cacheService := InitCacheService(redis.New(redis.Config{Addrs: []string{redisServer.Addr()}}))
+
+			// the bucket is refilled by 10 tokens per second up to 20 tokens
+			if test.tokens >= 0 {
+				// a timestamp in the future keeps the bucket from being refilled before the call
+				ts := time.Now().Add(time.Hour)
+				if test.elapsed > 0 {
+					ts = time.Now().Add(-test.elapsed)
+				}
+				redisServer.HSet(key, "tokens", strconv.FormatFloat(test.tokens, 'f', -1, 64), "ts", strconv.FormatInt(ts.UnixMilli(), 10))
+			}
+
+			limit, err := cacheService.TakeTokens(key, 10, 20, test.cost)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedGranted, limit.Granted)
+			assert.Equal(t, int64(20), limit.Limit)
+			assert.Equal(t, test.expectedRemaining, limit.Remaining)
+			assert.InDelta(t, float64(20-limit.Remaining)/10, limit.Reset.Seconds(), 0.2)
+			if test.expectRetryAfter {
+				assert.Greater(t, limit.RetryAfter, time.Duration(0))
+				assert.LessOrEqual(t, limit.RetryAfter, 100*time.Millisecond)
+			} else {
+				assert.Zero(t, limit.RetryAfter)
+			}
+			assert.True(t, redisServer.Exists(key))
+		})
+	}
+}
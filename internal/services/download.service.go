@@ -67,6 +67,11 @@ func (s *DownloadServiceImpl) DownloadEventData(ctx context.Context, projectID u
 			ip = ""
 		}
 
+		var clientFiredAt string
+		if row.ClientFiredAt.Valid {
+			clientFiredAt = row.ClientFiredAt.Time.String()
+		}
+
 		item := []string{
 			row.ID.String(),
 			row.EventType,
@@ -88,6 +93,27 @@ func (s *DownloadServiceImpl) DownloadEventData(ctx context.Context, projectID u
 			row.ReceivedAt.String(),
 			row.UserID.String(),
 			row.ProjectID.String(),
+			string(row.Properties),
+			row.BrowserVersion.String,
+			row.OsName.String,
+			row.OsVersion.String,
+			fmt.Sprintf("%t", row.IsBot),
+			row.VisitorHash.String,
+			row.DistinctID.String,
+			row.AnonymousID.String,
+			row.Referrer.String,
+			row.ReferrerDomain.String,
+			row.Channel.String,
+			row.UtmSource.String,
+			row.UtmMedium.String,
+			row.UtmCampaign.String,
+			row.UtmTerm.String,
+			row.UtmContent.String,
+			row.PagePath.String,
+			fmt.Sprintf("%d", row.Redactions.Int32),
+			fmt.Sprintf("%t", row.Trusted),
+			clientFiredAt,
+			fmt.Sprintf("%g", row.SampleRate),
 		}
 
 		result = append(result, item)
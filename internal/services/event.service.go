@@ -2,8 +2,17 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,7 +28,12 @@ import (
 
 type EventService interface {
 	CreateEvent(c *fiber.Ctx) error
+	CreateEvents(c *fiber.Ctx) error
+	CreatePixelEvent(c *fiber.Ctx) error
+	Identify(c *fiber.Ctx) error
+	ProcessorNames() []string
 	GetEvents(c *fiber.Ctx) error
+	GetEventPropertyBreakdown(c *fiber.Ctx) error
 	GetLiveEvents(ctx context.Context, userID uuid.UUID) ([]gen.GetLiveEventsRow, error)
 	GetLiveEventDetail(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, strategy string, limit int32) ([]gen.GetLiveEventsDetailRow, error)
 	GetWeeklyEventsChart(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*entities.EventSummaryChart, error)
@@ -33,6 +47,7 @@ type EventServiceImpl struct {
 	CacheService CacheService
 	AggrService  AggrService
 	WorkerPool   WorkerPool
+	Processors   *ProcessorChain
 	Repo         repositories.EventRepo
 	ProjectRepo  repositories.ProjectRepo
 }
@@ -42,6 +57,7 @@ func InitEventService(
 	cacheService CacheService,
 	aggrService AggrService,
 	workerPool WorkerPool,
+	processors *ProcessorChain,
 	repo repositories.EventRepo,
 	projectRepo repositories.ProjectRepo,
 ) EventServiceImpl {
@@ -50,59 +66,922 @@ func InitEventService(
 		CacheService: cacheService,
 		AggrService:  aggrService,
 		WorkerPool:   workerPool,
+		Processors:   processors,
 		Repo:         repo,
 		ProjectRepo:  projectRepo,
 	}
 }
 
 func (s *EventServiceImpl) CreateEvent(c *fiber.Ctx) error {
-	user := c.Locals("user").(*gen.FindUserByPublicKeyRow)
+	userID := ingestionUserID(c)
 	var input dto.CreateEventInput
 
 	if err := c.BodyParser(&input); err != nil {
 		log.Println(err)
-		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, err.Error())
+	}
+	clearServerFields(&input)
+
+	// Idempotency-Key header can be used in place of EventID
+	if input.EventID == "" {
+		input.EventID = c.Get("Idempotency-Key")
+	}
+
+	if err := s.ingestEvent(c, userID, &input); err != nil {
+		status, code := ingestionError(err)
+		return apiError(c, status, code, err.Error())
+	}
+
+	// go func() {
+	// 	if err := s.CacheService.InvalidateCaches([]string{
+	// 		configs.CACHE_LIVE_EVENTS(user.ID),
+	// 		configs.CACHE_LIVE_EVENT_SUMMARY(user.ID, projectUUID),
+	// 		configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastN),
+	// 		configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastHour),
+	// 		configs.CACHE_LIVE_EVENT_DETAIL_SUMMARY(user.ID, projectUUID),
+	// 		configs.CACHE_JSON_WEEKLY_EVENT_CHART(user.ID, projectUUID),
+	// 		configs.CACHE_JSON_EVENT_TYPE_CHART(user.ID, projectUUID),
+	// 		configs.CACHE_JSON_EVENT_LABEL_CHART(user.ID, projectUUID),
+	// 	}); err != nil {
+	// 		log.Println("Error invalidating all event's cache:", err)
+	// 	}
+	//
+	// 	// synchronize project summary
+	// 	// sync-ing project summary will only happen if the event is present
+	// 	// after the last project summarization. That means, if the event has not changed from the last
+	// 	// summarization, this function will insert nothing to db.
+	// 	if err := s.AggrService.SaveProjectAggr(context.Background(), projectUUID, user.ID); err != nil {
+	// 		log.Println("Error sync-ing project summary:", err)
+	// 	}
+	// }()
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// CreatePixelEvent tracks an event from a GET request and always responds with a 1x1 transparent GIF,
+// for places where scripts can not run such as emails or noscript fallbacks.
+func (s *EventServiceImpl) CreatePixelEvent(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByPublicKeyRow)
+
+	input := dto.CreateEventInput{
+		EventID:     c.Query("event_id"),
+		ProjectID:   c.Query("project_id"),
+		EventType:   c.Query("event_type"),
+		EventLabel:  c.Query("label"),
+		PageURL:     c.Query("page_url", c.Get(fiber.HeaderReferer)),
+		Referrer:    c.Query("referrer"),
+		DistinctID:  c.Query("distinct_id"),
+		AnonymousID: c.Query("anonymous_id"),
+		FiredAt:     time.Now().Format(time.RFC3339),
+	}
+
+	status := fiber.StatusOK
+	if err := s.ingestEvent(c, user.ID, &input); err != nil {
+		log.Println("Error tracking pixel event:", err)
+		status, _ = ingestionError(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/gif")
+	c.Set(fiber.HeaderCacheControl, "no-cache, no-store, must-revalidate, private")
+	c.Set(fiber.HeaderPragma, "no-cache")
+	c.Set(fiber.HeaderExpires, "0")
+
+	return c.Status(status).Send(constants.PIXEL_GIF)
+}
+
+// Identify links the anonymous id to a known user id. Previous events of the anonymous id
+// are merged into the user id, later events sent with only the anonymous id are stored with it.
+func (s *EventServiceImpl) Identify(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByPublicKeyRow)
+	var input dto.IdentifyInput
+
+	if err := c.BodyParser(&input); err != nil {
+		log.Println(err)
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, err.Error())
 	}
 
 	if err := s.UtilService.ValidateInput(input); err != "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err})
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, err)
+	}
+
+	if s.takeKeyTokens(c, user.ID, 1) == 0 {
+		return apiError(c, fiber.StatusTooManyRequests, entities.APIErrorRateLimited, errRateLimited.Error())
+	}
+
+	projectUUID := uuid.MustParse(input.ProjectID)
+	settings, err := s.checkProjectIngestion(user.ID, projectUUID)
+	if err != nil {
+		status, code := ingestionError(err)
+		return apiError(c, status, code, err.Error())
+	}
+
+	if err := s.checkOrigin(c, settings); err != nil {
+		status, code := ingestionError(err)
+		return apiError(c, status, code, err.Error())
+	}
+
+	if err := s.Repo.CreateIdentityAlias(context.Background(), &gen.CreateIdentityAliasParams{
+		ProjectID:   projectUUID,
+		AnonymousID: input.AnonymousID,
+		DistinctID:  input.DistinctID,
+		UserID:      user.ID,
+	}); err != nil {
+		log.Println("Error creating identity alias:", err)
+		return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "identity could not be stored")
+	}
+
+	merged, err := s.Repo.MergeAnonymousEvents(context.Background(), &gen.MergeAnonymousEventsParams{
+		ProjectID:   projectUUID,
+		AnonymousID: pgtype.Text{String: input.AnonymousID, Valid: true},
+		DistinctID:  pgtype.Text{String: input.DistinctID, Valid: true},
+		UserID:      user.ID,
+	})
+	if err != nil {
+		log.Println("Error merging anonymous events:", err)
+		return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "events could not be merged")
+	}
+
+	if merged > 0 {
+		s.queueProjectAggr(user.ID, projectUUID)
+	}
+
+	return c.JSON(fiber.Map{"merged": merged})
+}
+
+// ingestEvent validates and stores a single event.
+// dropped bot traffic and retried events return nil without being stored.
+func (s *EventServiceImpl) ingestEvent(c *fiber.Ctx, userID uuid.UUID, input *dto.CreateEventInput) error {
+	if err := s.UtilService.ValidateInput(*input); err != "" {
+		return errors.New(err)
+	}
+
+	if s.takeKeyTokens(c, userID, 1) == 0 {
+		return errRateLimited
+	}
+
+	projectUUID := uuid.MustParse(input.ProjectID)
+	if err := checkKeyProject(c, projectUUID); err != nil {
+		return err
+	}
+
+	settings, err := s.checkProjectIngestion(userID, projectUUID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkOrigin(c, settings); err != nil {
+		return err
+	}
+
+	trusted, err := s.verifySignature(c, settings)
+	if err != nil {
+		return err
+	}
+	input.Trusted = trusted
+
+	if err := s.correctFiredAt(input, settings, time.Now()); err != nil {
+		return err
+	}
+
+	if s.takeProjectTokens(c, settings, 1) == 0 {
+		return errRateLimited
+	}
+
+	if s.userQuotaLeft(c, userID) == 0 || s.projectQuotaLeft(c, userID, settings) == 0 {
+		return errQuotaExceeded
+	}
+
+	// event was already received, acknowledge the retry without storing it twice.
+	if !s.claimEventID(projectUUID, input.EventID) {
+		return nil
+	}
+
+	if s.applyPrivacySignal(c, input, settings) {
+		s.countSuppressed(userID, projectUUID, 1)
+		if !input.Anonymized {
+			return nil
+		}
+	}
+
+	// events dropped by a processor (e.g. bot traffic) are acknowledged but not stored
+	input = s.processEvent(c, userID, input, settings)
+	if input == nil {
+		return nil
+	}
+
+	payload := s.buildEventPayload(userID, input, settings)
+
+	if err := s.Repo.CreateEvent(context.Background(), &payload); err != nil {
+		// release the event id so the client can retry
+		if input.EventID != "" {
+			s.releaseEventIDs([]string{configs.CACHE_EVENT_ID(projectUUID, input.EventID)})
+		}
+		log.Println("Error storing event:", err)
+		return errStoreEvents
+	}
+
+	s.countMonthlyEvents(userID, settings, 1)
+
+	if input.Redactions > 0 {
+		s.countRedacted(userID, projectUUID, int64(input.Redactions))
+	}
+
+	s.queueProjectAggr(userID, projectUUID)
+	return nil
+}
+
+func (s *EventServiceImpl) CreateEvents(c *fiber.Ctx) error {
+	userID := ingestionUserID(c)
+	var input dto.CreateEventsInput
+
+	if err := c.BodyParser(&input); err != nil {
+		log.Println(err)
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, err.Error())
+	}
+
+	if len(input.Events) == 0 {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "Events field is required")
+	}
+	for i := range input.Events {
+		clearServerFields(&input.Events[i])
+	}
+
+	if len(input.Events) > constants.MAX_BATCH_EVENTS {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, fmt.Sprintf("maximum of %d events per batch", constants.MAX_BATCH_EVENTS))
 	}
 
+	// the signature is verified with the secret of a single project,
+	// a signed batch with events of another project could not be verified for them.
+	if c.Get(constants.HEADER_SIGNATURE) != "" && len(batchProjects(input.Events)) > 1 {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, errMixedSignedBatch.Error())
+	}
+
+	// the public key's bucket is taken once for the whole batch,
+	// events above the granted tokens are rate limited.
+	granted := s.takeKeyTokens(c, userID, int64(len(input.Events)))
+	if granted == 0 {
+		return apiError(c, fiber.StatusTooManyRequests, entities.APIErrorRateLimited, errRateLimited.Error())
+	}
+
+	userQuota := s.userQuotaLeft(c, userID)
+	if userQuota == 0 {
+		return apiError(c, fiber.StatusTooManyRequests, entities.APIErrorQuotaExceeded, errQuotaExceeded.Error())
+	}
+
+	// events are validated up front so only valid events are taken from their project's bucket,
+	// counted by the parsed id like projects so every spelling of an id is counted together.
+	invalid := make([]string, len(input.Events))
+	batchSizes := make(map[uuid.UUID]int64)
+	for i := range input.Events {
+		event := &input.Events[i]
+		if event.SentAt == "" {
+			event.SentAt = input.SentAt
+		}
+
+		if int64(i) >= granted {
+			break
+		}
+
+		if err := s.UtilService.ValidateInput(event); err != "" {
+			invalid[i] = err
+			continue
+		}
+		batchSizes[uuid.MustParse(event.ProjectID)]++
+	}
+
+	// all events of the batch are corrected against the same receive time.
+	receivedAt := time.Now()
+
+	results := make([]entities.EventBatchResult, len(input.Events))
+	payloads := make([]gen.CreateEventsParams, 0, len(input.Events))
+
+	// project checks are done once per project within the batch,
+	// the result is reused by the remaining events.
+	projects := make(map[uuid.UUID]projectIngestion)
+
+	// event ids claimed by this batch, released if the insert fails.
+	var claimedIDs []string
+
+	// number of events suppressed by a privacy signal per project.
+	suppressed := make(map[uuid.UUID]int64)
+
+	// number of values redacted per project.
+	redacted := make(map[uuid.UUID]int64)
+
+	// number of events accepted per project.
+	accepted := make(map[uuid.UUID]int64)
+
+	for i := range input.Events {
+		event := &input.Events[i]
+		results[i].Index = i
+
+		if int64(i) >= granted {
+			results[i].Status = entities.EventRateLimited
+			results[i].Error = errRateLimited.Error()
+			continue
+		}
+
+		if invalid[i] != "" {
+			results[i].Status = entities.EventRejected
+			results[i].Error = invalid[i]
+			continue
+		}
+
+		projectUUID := uuid.MustParse(event.ProjectID)
+		project, checked := projects[projectUUID]
+		if !checked {
+			project.Err = checkKeyProject(c, projectUUID)
+			if project.Err == nil {
+				project.Settings, project.Err = s.checkProjectIngestion(userID, projectUUID)
+			}
+			if project.Err == nil {
+				project.Err = s.checkOrigin(c, project.Settings)
+			}
+			if project.Err == nil {
+				project.Trusted, project.Err = s.verifySignature(c, project.Settings)
+			}
+			if project.Err == nil {
+				project.Tokens = s.takeProjectTokens(c, project.Settings, batchSizes[projectUUID])
+				project.Quota = s.projectQuotaLeft(c, userID, project.Settings)
+			}
+			projects[projectUUID] = project
+		}
+		if project.Err != nil {
+			results[i].Status = entities.EventRejected
+			results[i].Error = project.Err.Error()
+			continue
+		}
+		event.Trusted = project.Trusted
+
+		if err := s.correctFiredAt(event, project.Settings, receivedAt); err != nil {
+			results[i].Status = entities.EventRejected
+			results[i].Error = err.Error()
+			continue
+		}
+
+		if project.Tokens == 0 {
+			results[i].Status = entities.EventRateLimited
+			results[i].Error = errRateLimited.Error()
+			continue
+		}
+		project.Tokens--
+		projects[projectUUID] = project
+
+		if userQuota == 0 || project.Quota == 0 {
+			results[i].Status = entities.EventRateLimited
+			results[i].Error = errQuotaExceeded.Error()
+			continue
+		}
+
+		if !s.claimEventID(projectUUID, event.EventID) {
+			results[i].Status = entities.EventDuplicate
+			continue
+		}
+		if event.EventID != "" {
+			claimedIDs = append(claimedIDs, configs.CACHE_EVENT_ID(projectUUID, event.EventID))
+		}
+
+		if s.applyPrivacySignal(c, event, project.Settings) {
+			suppressed[projectUUID]++
+			if !event.Anonymized {
+				results[i].Status = entities.EventSuppressed
+				continue
+			}
+		}
+
+		event = s.processEvent(c, userID, event, project.Settings)
+		if event == nil {
+			results[i].Status = entities.EventDropped
+			continue
+		}
+		redacted[projectUUID] += int64(event.Redactions)
+
+		payload := s.buildEventPayload(userID, event, project.Settings)
+		payloads = append(payloads, gen.CreateEventsParams(payload))
+		results[i].Status = entities.EventAccepted
+
+		accepted[projectUUID]++
+		userQuota--
+		project.Quota--
+		projects[projectUUID] = project
+	}
+
+	if len(payloads) > 0 {
+		if _, err := s.Repo.CreateEvents(context.Background(), payloads); err != nil {
+			s.releaseEventIDs(claimedIDs)
+			log.Println("Error storing events:", err)
+			return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, errStoreEvents.Error())
+		}
+	}
+
+	for projectUUID, count := range accepted {
+		s.countMonthlyEvents(userID, projects[projectUUID].Settings, count)
+	}
+
+	for projectUUID, count := range suppressed {
+		s.countSuppressed(userID, projectUUID, count)
+	}
+
+	for projectUUID, count := range redacted {
+		if count > 0 {
+			s.countRedacted(userID, projectUUID, count)
+		}
+	}
+
+	// queue the aggregation job once per project instead of once per event.
+	for projectUUID, project := range projects {
+		if project.Err == nil {
+			s.queueProjectAggr(userID, projectUUID)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	return c.JSON(fiber.Map{
+		"total":        len(input.Events),
+		"accepted":     counts[entities.EventAccepted],
+		"duplicates":   counts[entities.EventDuplicate],
+		"dropped":      counts[entities.EventDropped],
+		"suppressed":   counts[entities.EventSuppressed],
+		"rate_limited": counts[entities.EventRateLimited],
+		"rejected":     counts[entities.EventRejected],
+		"results":      results,
+	})
+}
+
+// claimEventID marks the client supplied event id as seen for the project.
+// Returns false if the event id was already seen within the dedup window,
+// meaning the event is a retry. Events without id are always claimed.
+func (s *EventServiceImpl) claimEventID(projectID uuid.UUID, eventID string) bool {
+	if eventID == "" {
+		return true
+	}
+
+	claimed, err := s.CacheService.SetCacheNX(configs.CACHE_EVENT_ID(projectID, eventID), []byte{1}, constants.EVENT_DEDUP_WINDOW)
+	if err != nil {
+		// storing a possible duplicate is better than dropping the event
+		log.Println("Error claiming event id:", err)
+		return true
+	}
+
+	return claimed
+}
+
+func (s *EventServiceImpl) releaseEventIDs(keys []string) {
+	if err := s.CacheService.InvalidateCaches(keys); err != nil {
+		log.Println("Error releasing event ids:", err)
+	}
+}
+
+// projectIngestion is the result of checkProjectIngestion,
+// cached per project when ingesting a batch.
+type projectIngestion struct {
+	Settings *gen.FindProjectSettingsRow
+	Trusted  bool
+	Tokens   int64 // events granted by the project's rate limit
+	Quota    int64 // events left in the project's monthly quota
+	Err      error
+}
+
+// checkProjectIngestion checks whether the project exists within the user
+// and is still allowed to receive new events, returns the project's ingestion settings.
+func (s *EventServiceImpl) checkProjectIngestion(userID uuid.UUID, projectID uuid.UUID) (*gen.FindProjectSettingsRow, error) {
 	// check if the project id exist within user.
 	// if not dont proceed further.
-	projectUUID := uuid.MustParse(input.ProjectID)
-	exist, _ := s.Repo.CheckProjectWithinUserID(context.Background(), &gen.CheckProjectWithinUserIDParams{
-		ID:     projectUUID,
-		UserID: user.ID,
+	settings, err := s.ProjectRepo.FindSettings(context.Background(), &gen.FindProjectSettingsParams{
+		ID:     projectID,
+		UserID: userID,
 	})
-	if !exist {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project not found"})
+	if err != nil {
+		return nil, errProjectNotFound
+	}
+
+	return &settings, nil
+}
+
+var errProjectNotFound = errors.New("project not found")
+var errRateLimited = errors.New("rate limit exceeded")
+var errQuotaExceeded = errors.New("monthly event quota exceeded")
+var errStoreEvents = errors.New("events could not be stored")
+
+// ingestionError returns the status and error code of an error returned while ingesting an event,
+// other errors come from the validation of the event.
+func ingestionError(err error) (int, string) {
+	switch {
+	case errors.Is(err, errProjectNotFound):
+		return fiber.StatusNotFound, entities.APIErrorNotFound
+	case errors.Is(err, errOriginNotAllowed), errors.Is(err, errKeyProjectForbidden):
+		return fiber.StatusForbidden, entities.APIErrorForbidden
+	case errors.Is(err, errInvalidSignature):
+		return fiber.StatusUnauthorized, entities.APIErrorInvalidSignature
+	case errors.Is(err, errRateLimited):
+		return fiber.StatusTooManyRequests, entities.APIErrorRateLimited
+	case errors.Is(err, errQuotaExceeded):
+		return fiber.StatusTooManyRequests, entities.APIErrorQuotaExceeded
+	case errors.Is(err, errStoreEvents):
+		return fiber.StatusInternalServerError, entities.APIErrorInternal
+	default:
+		return fiber.StatusBadRequest, entities.APIErrorBadRequest
+	}
+}
+
+// apiError responds with the JSON error envelope of the /api/v1 routes.
+func apiError(c *fiber.Ctx, status int, code string, message string) error {
+	return c.Status(status).JSON(entities.APIError{Error: message, Code: code})
+}
+
+// takeKeyTokens takes the events from the public key's token bucket,
+// returns the number of events allowed.
+func (s *EventServiceImpl) takeKeyTokens(c *fiber.Ctx, userID uuid.UUID, count int64) int64 {
+	return s.takeTokens(c, configs.CACHE_RATE_LIMIT_KEY(userID), constants.PUBLIC_KEY_RATE_LIMIT, constants.PUBLIC_KEY_RATE_BURST, count)
+}
+
+// takeProjectTokens takes the events from the project's token bucket,
+// projects without their own limits use the default ones and are not limited if there are none.
+func (s *EventServiceImpl) takeProjectTokens(c *fiber.Ctx, settings *gen.FindProjectSettingsRow, count int64) int64 {
+	rate := int(settings.RateLimit)
+	if rate == 0 {
+		rate = constants.PROJECT_RATE_LIMIT
+	}
+	if rate == 0 {
+		return count
+	}
+	burst := int(settings.RateLimitBurst)
+	if burst == 0 {
+		burst = max(constants.PROJECT_RATE_BURST, rate)
+	}
+	return s.takeTokens(c, configs.CACHE_RATE_LIMIT_PROJECT(settings.ID), rate, burst, count)
+}
+
+// takeTokens returns the number of events granted by the bucket and sets the rate limit headers.
+// Events are allowed if redis is unavailable, ingestion should not depend on the limiter.
+func (s *EventServiceImpl) takeTokens(c *fiber.Ctx, key string, rate int, burst int, count int64) int64 {
+	limit, err := s.CacheService.TakeTokens(key, float64(rate), int64(burst), count)
+	if err != nil {
+		log.Println("Error taking rate limit tokens:", err)
+		return count
+	}
+
+	// keep the headers of the most restrictive bucket of the request
+	remaining, err := strconv.ParseInt(c.GetRespHeader(constants.HEADER_RATE_LIMIT_REMAINING), 10, 64)
+	if err == nil && remaining < limit.Remaining {
+		return limit.Granted
+	}
+
+	c.Set(constants.HEADER_RATE_LIMIT_LIMIT, strconv.FormatInt(limit.Limit, 10))
+	c.Set(constants.HEADER_RATE_LIMIT_REMAINING, strconv.FormatInt(limit.Remaining, 10))
+	c.Set(constants.HEADER_RATE_LIMIT_RESET, strconv.Itoa(int(math.Ceil(limit.Reset.Seconds()))))
+	if limit.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(limit.RetryAfter.Seconds()))))
+	}
+
+	return limit.Granted
+}
+
+// userQuotaLeft returns the number of events the user can still store this month.
+func (s *EventServiceImpl) userQuotaLeft(c *fiber.Ctx, userID uuid.UUID) int64 {
+	if constants.MONTHLY_EVENT_QUOTA == 0 {
+		return math.MaxInt64
 	}
 
-	// ---- DEMO ONLY -----
-	projectSize, _ := s.ProjectRepo.CountSize(context.Background(), &gen.CountProjectSizeParams{
-		UserID:    user.ID,
-		ProjectID: projectUUID,
+	count := s.monthlyEvents(configs.CACHE_MONTHLY_EVENTS(userID, time.Now().Format("2006-01")), func() (int64, error) {
+		return s.Repo.CountUserMonthlyEvents(context.Background(), userID)
 	})
-	// check if size is more than 10mb, if yes dont proceed further.
-	if projectSize > 10*1000 { // 10*1000 KB = 10 MB
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "DEMO NOTICE: Project already exceed maximum size of 10MB"})
+	return quotaLeft(c, constants.MONTHLY_EVENT_QUOTA, count)
+}
+
+// projectQuotaLeft returns the number of events the project can still store this month.
+func (s *EventServiceImpl) projectQuotaLeft(c *fiber.Ctx, userID uuid.UUID, settings *gen.FindProjectSettingsRow) int64 {
+	if settings.MonthlyQuota == 0 {
+		return math.MaxInt64
 	}
-	// ---- DEMO ONLY -----
 
-	// Get user IP
-	userIP := c.IP()
-	userLoc := s.UtilService.LookupIP(userIP)
+	count := s.monthlyEvents(configs.CACHE_PROJECT_MONTHLY_EVENTS(settings.ID, time.Now().Format("2006-01")), func() (int64, error) {
+		return s.Repo.CountProjectMonthlyEvents(context.Background(), &gen.CountProjectMonthlyEventsParams{
+			ProjectID: settings.ID,
+			UserID:    userID,
+		})
+	})
+	return quotaLeft(c, settings.MonthlyQuota, count)
+}
 
-	// bind user ip addr and loc
-	input.IPAddr = userIP
-	if userLoc != nil {
-		input.Country = userLoc.Country.Names["en"]
-		input.Region = userLoc.Continent.Names["en"]
-		input.City = userLoc.City.Names["en"]
+// quotaLeft sets Retry-After to the start of the next month once the quota is used up.
+func quotaLeft(c *fiber.Ctx, quota int64, count int64) int64 {
+	if count < quota {
+		return quota - count
 	}
 
-	payload := gen.CreateEventParams{
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(time.Until(nextMonth()).Seconds()))))
+	return 0
+}
+
+// monthlyEvents returns the number of events stored this month from the counter in the key,
+// the counter is seeded from the database once per month and incremented on each insert.
+func (s *EventServiceImpl) monthlyEvents(key string, countEvents func() (int64, error)) int64 {
+	cached, err := s.CacheService.GetCache(key)
+	if err == nil && cached != nil {
+		if count, err := strconv.ParseInt(string(cached), 10, 64); err == nil {
+			return count
+		}
+	}
+
+	count, err := countEvents()
+	if err != nil {
+		// storing over quota is better than dropping the event
+		log.Println("Error counting monthly events:", err)
+		return 0
+	}
+
+	// the counter outlives the month by a day, the next month uses a new key.
+	if _, err := s.CacheService.SetCacheNX(key, []byte(strconv.FormatInt(count, 10)), time.Until(nextMonth())+24*time.Hour); err != nil {
+		log.Println("Error caching monthly events:", err)
+	}
+	return count
+}
+
+// countMonthlyEvents adds the stored events to the monthly counters of the quotas in use.
+func (s *EventServiceImpl) countMonthlyEvents(userID uuid.UUID, settings *gen.FindProjectSettingsRow, count int64) {
+	month := time.Now().Format("2006-01")
+
+	var keys []string
+	if constants.MONTHLY_EVENT_QUOTA > 0 {
+		keys = append(keys, configs.CACHE_MONTHLY_EVENTS(userID, month))
+	}
+	if settings.MonthlyQuota > 0 {
+		keys = append(keys, configs.CACHE_PROJECT_MONTHLY_EVENTS(settings.ID, month))
+	}
+
+	for _, key := range keys {
+		if _, err := s.CacheService.IncrementCache(key, count, time.Until(nextMonth())+24*time.Hour); err != nil {
+			log.Println("Error counting monthly events:", err)
+		}
+	}
+}
+
+// nextMonth returns the start of the next calendar month, when the monthly quotas reset.
+func nextMonth() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+}
+
+var errTimestampOutOfRange = errors.New("FiredAt is outside of the accepted time window")
+
+// correctFiredAt corrects FiredAt with the client's SentAt, for devices with a wrong clock.
+// Timestamps still outside of the accepted window are clamped to it or rejected by the project's
+// timestamp policy. The client value is kept in ClientFiredAt whenever FiredAt is changed.
+func (s *EventServiceImpl) correctFiredAt(input *dto.CreateEventInput, settings *gen.FindProjectSettingsRow, receivedAt time.Time) error {
+	// a value sent by the client would be stored as if FiredAt had been corrected
+	input.ClientFiredAt = ""
+
+	firedAt := s.UtilService.ParseTimestamp(input.FiredAt)
+	corrected := s.UtilService.CorrectTimestamp(firedAt, s.UtilService.ParseTimestamp(input.SentAt), receivedAt)
+
+	earliest := receivedAt.Add(-constants.EVENT_MAX_AGE)
+	latest := receivedAt.Add(constants.EVENT_MAX_FUTURE)
+	if corrected.Before(earliest) || corrected.After(latest) {
+		if settings.TimestampPolicy == entities.TimestampPolicyReject {
+			return errTimestampOutOfRange
+		}
+		if corrected.Before(earliest) {
+			corrected = earliest
+		} else {
+			corrected = latest
+		}
+	}
+
+	if !corrected.Equal(firedAt) {
+		input.ClientFiredAt = input.FiredAt
+		input.FiredAt = corrected.Format(time.RFC3339Nano)
+	}
+	return nil
+}
+
+var errOriginNotAllowed = errors.New("origin is not allowed for this project")
+
+// checkOrigin enforces the project's allowed origins against the Origin or Referer header,
+// the project url is always allowed. Requests without both headers (e.g. server side clients)
+// and projects without url or allowed origins are not restricted.
+// The CORS allowed origin set by ProjectCORS is removed when the origin is not allowed.
+func (s *EventServiceImpl) checkOrigin(c *fiber.Ctx, settings *gen.FindProjectSettingsRow) error {
+	source := c.Get(fiber.HeaderOrigin)
+	if source == "" {
+		source = c.Get(fiber.HeaderReferer)
+	}
+	if source == "" {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(settings.AllowedOrigins)+1)
+	if settings.Url.Valid && settings.Url.String != "" {
+		allowed = append(allowed, settings.Url.String)
+	}
+	allowed = append(allowed, settings.AllowedOrigins...)
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if !s.UtilService.IsOriginAllowed(source, allowed) {
+		c.Response().Header.Del(fiber.HeaderAccessControlAllowOrigin)
+		return errOriginNotAllowed
+	}
+
+	return nil
+}
+
+var errInvalidSignature = errors.New("invalid signature")
+
+var errMixedSignedBatch = errors.New("signed batches must only contain events of one project")
+
+// batchProjects returns the distinct project ids of the batch, ids that can't be parsed are left out.
+func batchProjects(events []dto.CreateEventInput) map[uuid.UUID]bool {
+	projects := make(map[uuid.UUID]bool)
+	for _, event := range events {
+		if projectID, err := uuid.Parse(event.ProjectID); err == nil {
+			projects[projectID] = true
+		}
+	}
+	return projects
+}
+
+// verifySignature checks signed server-to-server requests. The X-Sentinel-Signature header is the
+// hex encoded HMAC-SHA256 of "<timestamp>.<body>" with the project's signing secret, where timestamp
+// is the X-Sentinel-Timestamp header in unix seconds. Returns true if the request is signed and valid,
+// requests without signature are not trusted but still accepted.
+func (s *EventServiceImpl) verifySignature(c *fiber.Ctx, settings *gen.FindProjectSettingsRow) (bool, error) {
+	signature := c.Get(constants.HEADER_SIGNATURE)
+	if signature == "" {
+		return false, nil
+	}
+
+	if !settings.SigningSecret.Valid || settings.SigningSecret.String == "" {
+		return false, fmt.Errorf("%w: project has no signing secret", errInvalidSignature)
+	}
+
+	timestamp := c.Get(constants.HEADER_SIGNATURE_TIMESTAMP)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid timestamp", errInvalidSignature)
+	}
+
+	age := time.Since(time.Unix(unix, 0))
+	if age > constants.SIGNATURE_TOLERANCE || age < -constants.SIGNATURE_TOLERANCE {
+		return false, fmt.Errorf("%w: timestamp is outside of the allowed window", errInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(settings.SigningSecret.String))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(c.Body())
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return false, errInvalidSignature
+	}
+
+	// a signature is only accepted once, the timestamp check bounds how long it has to be remembered
+	claimed, err := s.CacheService.SetCacheNX(configs.CACHE_SIGNATURE(settings.ID, expected), []byte{1}, 2*constants.SIGNATURE_TOLERANCE)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, fmt.Errorf("%w: request was already received", errInvalidSignature)
+	}
+
+	return true, nil
+}
+
+// applyPrivacySignal checks the DNT and Sec-GPC headers and the Consent field against the project's policy.
+// Returns true if the event is suppressed, either rejected or marked to be stored anonymized.
+func (s *EventServiceImpl) applyPrivacySignal(c *fiber.Ctx, input *dto.CreateEventInput, settings *gen.FindProjectSettingsRow) bool {
+	if settings.PrivacySignalPolicy != entities.PrivacySignalReject && settings.PrivacySignalPolicy != entities.PrivacySignalAnonymize {
+		return false
+	}
+
+	optedOut := c.Get("DNT") == "1" || c.Get("Sec-GPC") == "1" || (input.Consent != nil && !*input.Consent)
+	if !optedOut {
+		return false
+	}
+
+	input.Anonymized = settings.PrivacySignalPolicy == entities.PrivacySignalAnonymize
+	return true
+}
+
+// countSuppressed adds to the project's suppressed events counter, shown on the project page.
+func (s *EventServiceImpl) countSuppressed(userID uuid.UUID, projectID uuid.UUID, count int64) {
+	if err := s.ProjectRepo.IncrementSuppressed(context.Background(), &gen.IncrementSuppressedEventsParams{
+		ID:               projectID,
+		UserID:           userID,
+		SuppressedEvents: count,
+	}); err != nil {
+		log.Println("Error counting suppressed events:", err)
+	}
+}
+
+// countRedacted adds to the project's redacted values counter, shown on the project page.
+func (s *EventServiceImpl) countRedacted(userID uuid.UUID, projectID uuid.UUID, count int64) {
+	if err := s.ProjectRepo.IncrementRedacted(context.Background(), &gen.IncrementRedactedValuesParams{
+		ID:             projectID,
+		UserID:         userID,
+		RedactedValues: count,
+	}); err != nil {
+		log.Println("Error counting redacted values:", err)
+	}
+}
+
+// ProcessorNames returns the processors that can be enabled per project, in the order they run.
+func (s *EventServiceImpl) ProcessorNames() []string {
+	return s.Processors.Names()
+}
+
+// clearServerFields resets the fields set by the server. json:"-" only keeps them out of JSON bodies,
+// BodyParser still fills them from form and XML bodies.
+func clearServerFields(input *dto.CreateEventInput) {
+	input.IPAddr = ""
+	input.BrowserVersion = ""
+	input.OSName = ""
+	input.OSVersion = ""
+	input.Country = ""
+	input.Region = ""
+	input.City = ""
+	input.IsBot = false
+	input.Anonymized = false
+	input.Trusted = false
+	input.SampleRate = 0
+}
+
+// processEvent binds the client's IP and User-Agent to the event, then runs the project's
+// enabled processors. Returns nil if a processor dropped the event.
+func (s *EventServiceImpl) processEvent(c *fiber.Ctx, userID uuid.UUID, input *dto.CreateEventInput, settings *gen.FindProjectSettingsRow) *dto.CreateEventInput {
+	input.IPAddr = c.IP()
+
+	// only the redaction processor counts redacted values, a count sent by the client is dropped
+	input.Redactions = 0
+
+	// fallback to the request's own User-Agent if the payload does not provide one
+	if input.UserAgent == "" {
+		input.UserAgent = c.Get(fiber.HeaderUserAgent)
+	}
+
+	return s.Processors.Run(&ProcessorContext{
+		UserID:   userID,
+		Settings: settings,
+	}, input)
+}
+
+// buildEventPayload anonymizes the processed input according to the project's privacy settings,
+// then maps it into the insert params.
+func (s *EventServiceImpl) buildEventPayload(userID uuid.UUID, input *dto.CreateEventInput, settings *gen.FindProjectSettingsRow) gen.CreateEventParams {
+	// geolocation is done on the full IP by the processors, only the anonymized value is stored.
+	userIP := input.IPAddr
+	var visitorHash string
+	switch settings.IpMode {
+	case entities.IPModeTruncate:
+		input.IPAddr = s.UtilService.AnonymizeIP(userIP)
+	case entities.IPModeHash:
+		visitorHash = s.hashVisitor(settings.ID, userIP, time.Now())
+		input.IPAddr = ""
+	}
+	if settings.GeoCountryOnly {
+		input.City = ""
+	}
+
+	// events sent before identify only carry the anonymous id, resolve it to the known user id.
+	if input.DistinctID == "" && input.AnonymousID != "" {
+		input.DistinctID = s.resolveAnonymousID(input.ProjectID, input.AnonymousID)
+	}
+
+	// visitor opted out of tracking, keep the event without fields identifying the visitor.
+	if input.Anonymized {
+		input.IPAddr = ""
+		input.SessionID = ""
+		input.UserAgent = ""
+		input.DistinctID = ""
+		input.AnonymousID = ""
+		visitorHash = ""
+	}
+
+	attr := s.UtilService.ParseAttribution(input.PageURL, input.Referrer)
+	pagePath := s.UtilService.NormalizeURL(input.PageURL, entities.URLRules{
+		QueryAllowlist: settings.UrlQueryAllowlist,
+		PathPatterns:   settings.UrlPathPatterns,
+	})
+
+	// leave properties as NULL if none were sent
+	var properties json.RawMessage
+	if len(input.Properties) > 0 {
+		properties, _ = json.Marshal(input.Properties)
+	}
+
+	// events kept by sampling count as 1 / rate in the aggregations, the others count once
+	sampleRate := input.SampleRate
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	return gen.CreateEventParams{
 		// i need to insert the dto payload here, but its tedious to do it manually, F
 		EventType:        input.EventType,
 		EventLabel:       pgtype.Text{String: input.EventLabel, Valid: input.EventLabel != ""},
@@ -121,64 +1000,152 @@ func (s *EventServiceImpl) CreateEvent(c *fiber.Ctx) error {
 		ScreenResolution: pgtype.Text{String: input.ScreenResolution, Valid: input.ScreenResolution != ""},
 		FiredAt:          s.UtilService.ParseTimestamp(input.FiredAt),
 		ReceivedAt:       time.Now(),
-		UserID:           user.ID,
+		UserID:           userID,
 		ProjectID:        uuid.MustParse(input.ProjectID),
+		Properties:       properties,
+		BrowserVersion:   pgtype.Text{String: input.BrowserVersion, Valid: input.BrowserVersion != ""},
+		OsName:           pgtype.Text{String: input.OSName, Valid: input.OSName != ""},
+		OsVersion:        pgtype.Text{String: input.OSVersion, Valid: input.OSVersion != ""},
+		IsBot:            input.IsBot,
+		VisitorHash:      pgtype.Text{String: visitorHash, Valid: visitorHash != ""},
+		DistinctID:       pgtype.Text{String: input.DistinctID, Valid: input.DistinctID != ""},
+		AnonymousID:      pgtype.Text{String: input.AnonymousID, Valid: input.AnonymousID != ""},
+		Referrer:         pgtype.Text{String: input.Referrer, Valid: input.Referrer != ""},
+		ReferrerDomain:   pgtype.Text{String: attr.ReferrerDomain, Valid: attr.ReferrerDomain != ""},
+		Channel:          pgtype.Text{String: attr.Channel, Valid: attr.Channel != ""},
+		UtmSource:        pgtype.Text{String: attr.UTMSource, Valid: attr.UTMSource != ""},
+		UtmMedium:        pgtype.Text{String: attr.UTMMedium, Valid: attr.UTMMedium != ""},
+		UtmCampaign:      pgtype.Text{String: attr.UTMCampaign, Valid: attr.UTMCampaign != ""},
+		UtmTerm:          pgtype.Text{String: attr.UTMTerm, Valid: attr.UTMTerm != ""},
+		UtmContent:       pgtype.Text{String: attr.UTMContent, Valid: attr.UTMContent != ""},
+		PagePath:         pgtype.Text{String: pagePath, Valid: pagePath != ""},
+		Redactions:       pgtype.Int4{Int32: int32(input.Redactions), Valid: input.Redactions > 0},
+		Trusted:          input.Trusted,
+		SampleRate:       sampleRate,
+		ClientFiredAt:    pgtype.Timestamptz{Time: s.UtilService.ParseTimestamp(input.ClientFiredAt), Valid: input.ClientFiredAt != ""},
 	}
+}
 
-	if err := s.Repo.CreateEvent(context.Background(), &payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+// resolveAnonymousID returns the user id the anonymous id was merged into, empty if it was never identified.
+func (s *EventServiceImpl) resolveAnonymousID(projectID string, anonymousID string) string {
+	distinctID, err := s.Repo.FindIdentityAlias(context.Background(), &gen.FindIdentityAliasParams{
+		ProjectID:   uuid.MustParse(projectID),
+		AnonymousID: anonymousID,
+	})
+	if err != nil {
+		return ""
 	}
+	return distinctID
+}
+
+// dailySalt is the in-memory copy of the current day's visitor salt.
+var dailySalt struct {
+	sync.Mutex
+	day  string
+	salt []byte
+}
+
+// hashVisitor hashes the client IP with a salt that rotates daily, allowing unique visitors
+// to be counted within a day without storing the IP.
+func (s *EventServiceImpl) hashVisitor(projectID uuid.UUID, ip string, now time.Time) string {
+	hash := sha256.New()
+	hash.Write(s.visitorSalt(now.UTC().Format(time.DateOnly)))
+	hash.Write(projectID[:])
+	hash.Write([]byte(ip))
+	return hex.EncodeToString(hash.Sum(nil))
+}
 
+// visitorSalt returns the salt of the given day. The salt is shared through redis so every
+// instance produces the same hash, and is never persisted once it expires.
+func (s *EventServiceImpl) visitorSalt(day string) []byte {
+	dailySalt.Lock()
+	defer dailySalt.Unlock()
+
+	if dailySalt.day == day {
+		return dailySalt.salt
+	}
+
+	key := configs.CACHE_VISITOR_SALT(day)
+	salt := []byte(s.UtilService.GenerateRandomID(32))
+
+	// another instance may have already created the salt of the day, use theirs.
+	if _, err := s.CacheService.SetCacheNX(key, salt, constants.VISITOR_SALT_TTL); err != nil {
+		log.Println("Error storing visitor salt:", err)
+	} else if stored, err := s.CacheService.GetCache(key); err == nil && len(stored) > 0 {
+		salt = stored
+	}
+
+	dailySalt.day = day
+	dailySalt.salt = salt
+	return salt
+}
+
+// queueProjectAggr sends a job to the worker pool to invalidate the project caches
+// and re-aggregate the project summary.
+func (s *EventServiceImpl) queueProjectAggr(userID uuid.UUID, projectID uuid.UUID) {
 	s.WorkerPool.jobChan <- WorkerJob{
 		Timestamp: time.Now(),
-		UserID:    user.ID,
-		ProjectID: projectUUID,
+		UserID:    userID,
+		ProjectID: projectID,
 		Callback: func() {
 			// Invalidate all caches related to current event inside redis storage (if any)
 			if err := s.CacheService.InvalidateCaches([]string{
-				configs.CACHE_LIVE_EVENTS(user.ID),
-				configs.CACHE_LIVE_EVENT_SUMMARY(user.ID, projectUUID),
-				configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastN),
-				configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastHour),
-				configs.CACHE_LIVE_EVENT_DETAIL_SUMMARY(user.ID, projectUUID),
-				configs.CACHE_JSON_WEEKLY_EVENT_CHART(user.ID, projectUUID),
-				configs.CACHE_JSON_EVENT_TYPE_CHART(user.ID, projectUUID),
-				configs.CACHE_JSON_EVENT_LABEL_CHART(user.ID, projectUUID),
+				configs.CACHE_LIVE_EVENTS(userID),
+				configs.CACHE_LIVE_EVENT_SUMMARY(userID, projectID),
+				configs.CACHE_LIVE_EVENT(userID, projectID, entities.EventsByLastN),
+				configs.CACHE_LIVE_EVENT(userID, projectID, entities.EventsByLastHour),
+				configs.CACHE_LIVE_EVENT(userID, projectID, entities.EventsByTrusted),
+				configs.CACHE_LIVE_EVENT_DETAIL_SUMMARY(userID, projectID),
+				configs.CACHE_JSON_WEEKLY_EVENT_CHART(userID, projectID),
+				configs.CACHE_JSON_EVENT_TYPE_CHART(userID, projectID),
+				configs.CACHE_JSON_EVENT_LABEL_CHART(userID, projectID),
 			}); err != nil {
 				log.Println("Error invalidating all event's cache:", err)
 			}
 
 			// Save project aggregations (summary)
-			if err := s.AggrService.SaveProjectAggr(context.Background(), projectUUID, user.ID); err != nil {
+			if err := s.AggrService.SaveProjectAggr(context.Background(), projectID, userID); err != nil {
 				log.Println("Error sync-ing project summary:", err)
 			}
 		},
 	}
+}
 
-	// go func() {
-	// 	if err := s.CacheService.InvalidateCaches([]string{
-	// 		configs.CACHE_LIVE_EVENTS(user.ID),
-	// 		configs.CACHE_LIVE_EVENT_SUMMARY(user.ID, projectUUID),
-	// 		configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastN),
-	// 		configs.CACHE_LIVE_EVENT(user.ID, projectUUID, entities.EventsByLastHour),
-	// 		configs.CACHE_LIVE_EVENT_DETAIL_SUMMARY(user.ID, projectUUID),
-	// 		configs.CACHE_JSON_WEEKLY_EVENT_CHART(user.ID, projectUUID),
-	// 		configs.CACHE_JSON_EVENT_TYPE_CHART(user.ID, projectUUID),
-	// 		configs.CACHE_JSON_EVENT_LABEL_CHART(user.ID, projectUUID),
-	// 	}); err != nil {
-	// 		log.Println("Error invalidating all event's cache:", err)
-	// 	}
-	//
-	// 	// synchronize project summary
-	// 	// sync-ing project summary will only happen if the event is present
-	// 	// after the last project summarization. That means, if the event has not changed from the last
-	// 	// summarization, this function will insert nothing to db.
-	// 	if err := s.AggrService.SaveProjectAggr(context.Background(), projectUUID, user.ID); err != nil {
-	// 		log.Println("Error sync-ing project summary:", err)
-	// 	}
-	// }()
+var errKeyProjectForbidden = errors.New("the key is restricted to another project")
 
-	return c.SendStatus(fiber.StatusOK)
+// keyProjectID returns the project read by a private key request. Keys restricted to a project
+// read that project when none is given and are rejected for other projects.
+func keyProjectID(user *gen.FindUserByPrivateKeyRow, projectID uuid.UUID) (uuid.UUID, error) {
+	if !user.ProjectID.Valid {
+		return projectID, nil
+	}
+
+	keyProject := uuid.UUID(user.ProjectID.Bytes)
+	if projectID != uuid.Nil && projectID != keyProject {
+		return uuid.Nil, errKeyProjectForbidden
+	}
+
+	return keyProject, nil
+}
+
+// ingestionUserID returns the user of the key sending events,
+// events are sent with the public key or with a private key from a server.
+func ingestionUserID(c *fiber.Ctx) uuid.UUID {
+	if user, ok := c.Locals("user").(*gen.FindUserByPrivateKeyRow); ok {
+		return user.ID
+	}
+	return c.Locals("user").(*gen.FindUserByPublicKeyRow).ID
+}
+
+// checkKeyProject rejects events sent with a private key restricted to another project.
+func checkKeyProject(c *fiber.Ctx, projectID uuid.UUID) error {
+	user, ok := c.Locals("user").(*gen.FindUserByPrivateKeyRow)
+	if !ok {
+		return nil
+	}
+
+	_, err := keyProjectID(user, projectID)
+	return err
 }
 
 func (s *EventServiceImpl) GetEvents(c *fiber.Ctx) error {
@@ -186,14 +1153,165 @@ func (s *EventServiceImpl) GetEvents(c *fiber.Ctx) error {
 
 	limitQuery := c.Query("limit", "100")
 	limit, err := strconv.Atoi(limitQuery)
+	if err != nil || limit < 1 || limit > constants.MAX_EVENTS_PAGE_SIZE {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, fmt.Sprintf("limit must be between 1 and %d", constants.MAX_EVENTS_PAGE_SIZE))
+	}
+
+	intervalQuery := c.Query("interval", "all_time")
+	interval, ok := constants.Intervals[intervalQuery]
+	if !ok {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid interval")
+	}
+
+	projectID := c.Query("project_id")
+	var projectUUID uuid.UUID
+	if projectID != "" {
+		projectUUID, err = uuid.Parse(projectID)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid project id")
+		}
+	}
+
+	projectUUID, err = keyProjectID(user, projectUUID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid limit"})
+		return apiError(c, fiber.StatusForbidden, entities.APIErrorForbidden, err.Error())
+	}
+
+	// newest events come first by default, asc is meant for incremental syncs
+	order := c.Query("order", "desc")
+	if order != "asc" && order != "desc" {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid order")
+	}
+
+	// properties filter is a JSON object, events must contain all of its key-value pairs
+	properties := []byte("{}")
+	if propertiesQuery := c.Query("properties"); propertiesQuery != "" {
+		var filter map[string]interface{}
+		if err := json.Unmarshal([]byte(propertiesQuery), &filter); err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid properties filter")
+		}
+		properties, _ = json.Marshal(filter)
+	}
+
+	// trusted filter returns only signed (true) or unsigned (false) events
+	var trusted pgtype.Bool
+	if trustedQuery := c.Query("trusted"); trustedQuery != "" {
+		value, err := strconv.ParseBool(trustedQuery)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid trusted filter")
+		}
+		trusted = pgtype.Bool{Bool: value, Valid: true}
+	}
+
+	// from is inclusive and to is exclusive, both are compared to the received time
+	var receivedFrom, receivedTo pgtype.Timestamptz
+	if fromQuery := c.Query("from"); fromQuery != "" {
+		from, err := time.Parse(time.RFC3339Nano, fromQuery)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid from timestamp")
+		}
+		receivedFrom = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+	if toQuery := c.Query("to"); toQuery != "" {
+		to, err := time.Parse(time.RFC3339Nano, toQuery)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid to timestamp")
+		}
+		receivedTo = pgtype.Timestamptz{Time: to, Valid: true}
+	}
+
+	// cursor is the next_cursor of the previous page, it must be used with the same order
+	var cursorReceivedAt pgtype.Timestamptz
+	var cursorID pgtype.UUID
+	if cursorQuery := c.Query("cursor"); cursorQuery != "" {
+		receivedAt, id, err := s.UtilService.DecodeCursor(cursorQuery)
+		if err != nil {
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, err.Error())
+		}
+		cursorReceivedAt = pgtype.Timestamptz{Time: receivedAt, Valid: true}
+		cursorID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	textFilter := func(key string) pgtype.Text {
+		value := c.Query(key)
+		return pgtype.Text{String: value, Valid: value != ""}
+	}
+
+	// page_url is a prefix matched with LIKE, so its wildcards are escaped
+	pageURL := textFilter("page_url")
+	pageURL.String = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(pageURL.String)
+
+	params := gen.GetEventsParams{
+		UserID:           user.ID,
+		LimitCount:       int32(limit),
+		Interval:         int32(interval),
+		ProjectID:        projectUUID,
+		Properties:       properties,
+		Trusted:          trusted,
+		ReceivedFrom:     receivedFrom,
+		ReceivedTo:       receivedTo,
+		EventType:        textFilter("event_type"),
+		EventLabel:       textFilter("label"),
+		Country:          textFilter("country"),
+		SessionID:        textFilter("session_id"),
+		PageUrlPrefix:    pageURL,
+		DeviceType:       textFilter("device"),
+		CursorReceivedAt: cursorReceivedAt,
+		CursorID:         cursorID,
+	}
+
+	events := []gen.GetEventsRow{}
+	if order == "asc" {
+		rows, err := s.Repo.GetEventsAscending(context.Background(), (*gen.GetEventsAscendingParams)(&params))
+		if err != nil {
+			log.Println("Error getting events:", err)
+			return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "events could not be retrieved")
+		}
+		for _, row := range rows {
+			events = append(events, gen.GetEventsRow(row))
+		}
+	} else {
+		rows, err := s.Repo.GetEvents(context.Background(), &params)
+		if err != nil {
+			log.Println("Error getting events:", err)
+			return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "events could not be retrieved")
+		}
+		events = append(events, rows...)
+	}
+
+	// a full page may be followed by more events, a shorter one is the last page
+	var nextCursor *string
+	if len(events) == limit {
+		last := events[len(events)-1]
+		cursor := s.UtilService.EncodeCursor(last.ReceivedAt, last.ID)
+		nextCursor = &cursor
+	}
+
+	return c.JSON(fiber.Map{
+		"total":       len(events),
+		"data":        events,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (s *EventServiceImpl) GetEventPropertyBreakdown(c *fiber.Ctx) error {
+	user := c.Locals("user").(*gen.FindUserByPrivateKeyRow)
+
+	propertyKey := c.Params("key")
+	if propertyKey == "" || len(propertyKey) > 100 {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid property key")
+	}
+
+	limitQuery := c.Query("limit", "100")
+	limit, err := strconv.Atoi(limitQuery)
+	if err != nil || limit < 1 || limit > constants.MAX_EVENTS_PAGE_SIZE {
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, fmt.Sprintf("limit must be between 1 and %d", constants.MAX_EVENTS_PAGE_SIZE))
 	}
 
 	intervalQuery := c.Query("interval", "all_time")
 	interval, ok := constants.Intervals[intervalQuery]
 	if !ok {
-		return c.SendString("invalid interval")
+		return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid interval")
 	}
 
 	projectID := c.Query("project_id")
@@ -201,23 +1319,31 @@ func (s *EventServiceImpl) GetEvents(c *fiber.Ctx) error {
 	if projectID != "" {
 		projectUUID, err = uuid.Parse(projectID)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid project id"})
+			return apiError(c, fiber.StatusBadRequest, entities.APIErrorBadRequest, "invalid project id")
 		}
 	}
 
-	events, err := s.Repo.GetEvents(context.Background(), &gen.GetEventsParams{
-		UserID:     user.ID,
-		LimitCount: int32(limit),
-		Interval:   int32(interval),
-		ProjectID:  projectUUID,
+	projectUUID, err = keyProjectID(user, projectUUID)
+	if err != nil {
+		return apiError(c, fiber.StatusForbidden, entities.APIErrorForbidden, err.Error())
+	}
+
+	breakdown, err := s.Repo.GetEventPropertyBreakdown(context.Background(), &gen.GetEventPropertyBreakdownParams{
+		UserID:      user.ID,
+		PropertyKey: propertyKey,
+		LimitCount:  int32(limit),
+		Interval:    int32(interval),
+		ProjectID:   projectUUID,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		log.Println("Error getting event property breakdown:", err)
+		return apiError(c, fiber.StatusInternalServerError, entities.APIErrorInternal, "property breakdown could not be retrieved")
 	}
 
 	return c.JSON(fiber.Map{
-		"total": len(events),
-		"data":  events,
+		"property": propertyKey,
+		"total":    len(breakdown),
+		"data":     breakdown,
 	})
 }
 
@@ -227,10 +1353,11 @@ func (s *EventServiceImpl) GetLiveEvents(ctx context.Context, userID uuid.UUID)
 
 func (s *EventServiceImpl) GetLiveEventDetail(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, isNFetch string, limit int32) ([]gen.GetLiveEventsDetailRow, error) {
 	return s.Repo.GetLiveEventDetail(ctx, &gen.GetLiveEventsDetailParams{
-		ProjectID:  projectID,
-		UserID:     userID,
-		Bylasthour: isNFetch == entities.EventsByLastHour,
-		LimitCount: limit,
+		ProjectID:   projectID,
+		UserID:      userID,
+		Bylasthour:  isNFetch == entities.EventsByLastHour,
+		Trustedonly: isNFetch == entities.EventsByTrusted,
+		LimitCount:  limit,
 	})
 }
 
@@ -0,0 +1,1792 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"image/gif"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/storage/redis/v2"
+	"github.com/google/uuid"
+	"github.com/hubkudev/sentinel/configs"
+	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/dto"
+	"github.com/hubkudev/sentinel/internal/entities"
+	"github.com/hubkudev/sentinel/internal/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type eventTest struct {
+	service     EventServiceImpl
+	eventRepo   *mocks.EventRepo
+	projectRepo *mocks.ProjectRepo
+	redis       *miniredis.Miniredis
+	userID      uuid.UUID
+}
+
+// initEventTest returns an event service backed by mocked repositories and an in-memory redis.
+func initEventTest(t *testing.T) *eventTest {
+	validate := validator.New()
+	_ = validate.RegisterValidation("timestamp", constants.IsISO8601Date)
+
+	redisServer := miniredis.RunT(t)
+	cacheService := InitCacheService(redis.New(redis.Config{Addrs: []string{redisServer.Addr()}}))
+	utilService := InitUtilService(validate, mocks.NewIPDBRepo(t))
+
+	test := &eventTest{
+		eventRepo:   mocks.NewEventRepo(t),
+		projectRepo: mocks.NewProjectRepo(t),
+		redis:       redisServer,
+		userID:      uuid.New(),
+	}
+	test.service = InitEventService(&utilService, &cacheService, nil, *InitWorkerPool(1, 100), InitProcessorChain(), test.eventRepo, test.projectRepo)
+
+	// the monthly quota is counted from the database once per month
+	test.eventRepo.On("CountUserMonthlyEvents", mock.Anything, test.userID).Return(int64(0), nil).Maybe()
+
+	return test
+}
+
+// app routes the handler behind a public key user, like the ingestion routes.
+func (e *eventTest) app(path string, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Post(path, func(c *fiber.Ctx) error {
+		c.Locals("user", &gen.FindUserByPublicKeyRow{ID: e.userID})
+		return c.Next()
+	}, handler)
+	return app
+}
+
+// readApp routes the handler behind a private key user, like the /api/v1 read routes.
+func (e *eventTest) readApp(path string, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get(path, func(c *fiber.Ctx) error {
+		c.Locals("user", &gen.FindUserByPrivateKeyRow{ID: e.userID})
+		return c.Next()
+	}, handler)
+	return app
+}
+
+// project registers the settings of a project owned by the test user.
+func (e *eventTest) project(settings gen.FindProjectSettingsRow) uuid.UUID {
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	e.projectRepo.On("FindSettings", mock.Anything, &gen.FindProjectSettingsParams{
+		ID:     settings.ID,
+		UserID: e.userID,
+	}).Return(settings, nil).Maybe()
+	return settings.ID
+}
+
+func newEvent(projectID string) map[string]interface{} {
+	return map[string]interface{}{
+		"ProjectID": projectID,
+		"EventType": "click",
+		"FiredAt":   time.Now().Format(time.RFC3339),
+	}
+}
+
+type batchResponse struct {
+	Total       int
+	Accepted    int
+	Duplicates  int
+	RateLimited int `json:"rate_limited"`
+	Rejected    int
+	Results     []entities.EventBatchResult
+	Error       string
+}
+
+// sign sets the signature headers of a server-to-server request.
+func sign(req *http.Request, body []byte, secret string, timestamp time.Time) {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	req.Header.Set(constants.HEADER_SIGNATURE_TIMESTAMP, unix)
+	req.Header.Set(constants.HEADER_SIGNATURE, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func sendBatch(t *testing.T, app *fiber.App, events []map[string]interface{}) (int, batchResponse) {
+	return sendSignedBatch(t, app, events, "")
+}
+
+// sendSignedBatch signs the batch with the secret unless it is empty.
+func sendSignedBatch(t *testing.T, app *fiber.App, events []map[string]interface{}, secret string) (int, batchResponse) {
+	body, _ := json.Marshal(map[string]interface{}{"Events": events})
+	req := httptest.NewRequest(fiber.MethodPost, "/batch", strings.NewReader(string(body)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if secret != "" {
+		sign(req, body, secret, time.Now())
+	}
+
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+
+	var result batchResponse
+	raw, _ := io.ReadAll(res.Body)
+	assert.NoError(t, json.Unmarshal(raw, &result), string(raw))
+	return res.StatusCode, result
+}
+
+func TestCreateEvents(t *testing.T) {
+	t.Run("Should return the status of each event", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		missingID := uuid.New()
+		test.projectRepo.On("FindSettings", mock.Anything, &gen.FindProjectSettingsParams{
+			ID:     missingID,
+			UserID: test.userID,
+		}).Return(gen.FindProjectSettingsRow{}, errors.New("no rows")).Once()
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 2
+		})).Return(int64(2), nil).Once()
+
+		withID := newEvent(projectID.String())
+		withID["EventID"] = "event-1"
+		invalid := newEvent(projectID.String())
+		delete(invalid, "EventType")
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{
+			newEvent(projectID.String()),
+			withID,
+			withID,
+			invalid,
+			newEvent(missingID.String()),
+			newEvent("not-a-uuid"),
+		})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 6, result.Total)
+		assert.Equal(t, 2, result.Accepted)
+		assert.Equal(t, 1, result.Duplicates)
+		assert.Equal(t, 3, result.Rejected)
+
+		statuses := make([]string, len(result.Results))
+		for i, item := range result.Results {
+			assert.Equal(t, i, item.Index)
+			statuses[i] = item.Status
+		}
+		assert.Equal(t, []string{
+			entities.EventAccepted,
+			entities.EventAccepted,
+			entities.EventDuplicate,
+			entities.EventRejected,
+			entities.EventRejected,
+			entities.EventRejected,
+		}, statuses)
+		assert.Equal(t, "project not found", result.Results[4].Error)
+		assert.NotEmpty(t, result.Results[5].Error)
+	})
+
+	t.Run("Should reject batches over the maximum size", func(t *testing.T) {
+		test := initEventTest(t)
+
+		events := make([]map[string]interface{}, constants.MAX_BATCH_EVENTS+1)
+		for i := range events {
+			events[i] = newEvent(uuid.NewString())
+		}
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), events)
+
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Contains(t, result.Error, "maximum")
+	})
+
+	t.Run("Should reject an empty batch", func(t *testing.T) {
+		test := initEventTest(t)
+
+		status, _ := sendBatch(t, test.app("/batch", test.service.CreateEvents), nil)
+
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+
+	t.Run("Should queue one aggregation job and take the tokens of valid events once per project", func(t *testing.T) {
+		test := initEventTest(t)
+		limits := gen.FindProjectSettingsRow{RateLimit: 10, RateLimitBurst: 20}
+		first := test.project(limits)
+		second := test.project(limits)
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.Anything).Return(int64(4), nil).Once()
+
+		// the project id in upper case fails validation, it must not take a token of the project
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{
+			newEvent(first.String()),
+			newEvent(strings.ToUpper(first.String())),
+			newEvent(first.String()),
+			newEvent(second.String()),
+			newEvent(second.String()),
+		})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 4, result.Accepted)
+		assert.Equal(t, 1, result.Rejected)
+		test.projectRepo.AssertNumberOfCalls(t, "FindSettings", 2)
+
+		jobs := make(map[uuid.UUID]int)
+		for len(test.service.WorkerPool.jobChan) > 0 {
+			job := <-test.service.WorkerPool.jobChan
+			jobs[job.ProjectID]++
+		}
+		assert.Equal(t, map[uuid.UUID]int{first: 1, second: 1}, jobs)
+
+		for projectID, count := range map[uuid.UUID]float64{first: 2, second: 2} {
+			tokens, err := strconv.ParseFloat(test.redis.HGet(configs.CACHE_RATE_LIMIT_PROJECT(projectID), "tokens"), 64)
+			assert.NoError(t, err)
+			assert.InDelta(t, float64(limits.RateLimitBurst)-count, tokens, 0.5)
+		}
+	})
+}
+
+// sendEvent posts a single event and returns the response.
+func sendEvent(t *testing.T, app *fiber.App, event map[string]interface{}) *http.Response {
+	body, _ := json.Marshal(event)
+	req := httptest.NewRequest(fiber.MethodPost, "/event", strings.NewReader(string(body)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+	return res
+}
+
+// sendForm posts a single event as a form body, which BodyParser decodes without the json tags.
+func sendForm(t *testing.T, app *fiber.App, values url.Values) *http.Response {
+	req := httptest.NewRequest(fiber.MethodPost, "/event", strings.NewReader(values.Encode()))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+	return res
+}
+
+// formEvent returns the form values of a valid event of the project.
+func formEvent(projectID uuid.UUID) url.Values {
+	return url.Values{
+		"ProjectID": {projectID.String()},
+		"EventType": {"click"},
+		"FiredAt":   {time.Now().Format(time.RFC3339)},
+	}
+}
+
+// emptyBucket stores a bucket without tokens that is not refilled before the request.
+func (e *eventTest) emptyBucket(key string) {
+	e.redis.HSet(key, "tokens", "0", "ts", strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10))
+}
+
+func TestCreateEventServerFields(t *testing.T) {
+	t.Run("Should not store the server fields of a form body", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.SampleRate == 1 && !payload.IsBot && !payload.Trusted && !payload.Country.Valid && !payload.OsName.Valid
+		})).Return(nil).Once()
+
+		values := formEvent(projectID)
+		values.Set("SampleRate", "0.0001")
+		values.Set("IsBot", "true")
+		values.Set("Trusted", "true")
+		values.Set("Country", "XX")
+		values.Set("OSName", "Fake OS")
+
+		res := sendForm(t, test.app("/event", test.service.CreateEvent), values)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Should not store a negative sample rate", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.SampleRate == 1
+		})).Return(nil).Once()
+
+		values := formEvent(projectID)
+		values.Set("SampleRate", "-1")
+
+		res := sendForm(t, test.app("/event", test.service.CreateEvent), values)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+}
+
+func TestCreateEventRedactions(t *testing.T) {
+	t.Run("Should not count redactions sent by the client", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return !payload.Redactions.Valid
+		})).Return(nil).Once()
+
+		values := formEvent(projectID)
+		values.Set("Redactions", "99999")
+
+		res := sendForm(t, test.app("/event", test.service.CreateEvent), values)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		test.projectRepo.AssertNotCalled(t, "IncrementRedacted", mock.Anything, mock.Anything)
+	})
+}
+
+// sendIdempotentEvent posts a single event with the Idempotency-Key header.
+func sendIdempotentEvent(t *testing.T, app *fiber.App, event map[string]interface{}, key string) *http.Response {
+	body, _ := json.Marshal(event)
+	req := httptest.NewRequest(fiber.MethodPost, "/event", strings.NewReader(string(body)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", key)
+
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestEventIDDedup(t *testing.T) {
+	t.Run("Should store an event id once and acknowledge its retries", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+		app := test.app("/event", test.service.CreateEvent)
+
+		event := newEvent(projectID.String())
+		event["EventID"] = "event-1"
+
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		test.eventRepo.AssertNumberOfCalls(t, "CreateEvent", 1)
+		assert.True(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "event-1")))
+	})
+
+	t.Run("Should use the Idempotency-Key header without an event id", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+		app := test.app("/event", test.service.CreateEvent)
+
+		event := newEvent(projectID.String())
+
+		assert.Equal(t, fiber.StatusOK, sendIdempotentEvent(t, app, event, "key-1").StatusCode)
+		assert.Equal(t, fiber.StatusOK, sendIdempotentEvent(t, app, event, "key-1").StatusCode)
+		test.eventRepo.AssertNumberOfCalls(t, "CreateEvent", 1)
+		assert.True(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "key-1")))
+	})
+
+	t.Run("Should store events without an id every time", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Twice()
+		app := test.app("/event", test.service.CreateEvent)
+
+		event := newEvent(projectID.String())
+
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		test.eventRepo.AssertNumberOfCalls(t, "CreateEvent", 2)
+	})
+
+	t.Run("Should claim event ids per project", func(t *testing.T) {
+		test := initEventTest(t)
+		first := test.project(gen.FindProjectSettingsRow{})
+		second := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Twice()
+		app := test.app("/event", test.service.CreateEvent)
+
+		for _, projectID := range []uuid.UUID{first, second} {
+			event := newEvent(projectID.String())
+			event["EventID"] = "event-1"
+			assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		}
+		test.eventRepo.AssertNumberOfCalls(t, "CreateEvent", 2)
+	})
+
+	t.Run("Should release the event id when the event could not be stored", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+		app := test.app("/event", test.service.CreateEvent)
+
+		event := newEvent(projectID.String())
+		event["EventID"] = "event-1"
+
+		assert.Equal(t, fiber.StatusInternalServerError, sendEvent(t, app, event).StatusCode)
+		assert.False(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "event-1")))
+
+		// the retry is stored instead of being acknowledged as a duplicate
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, app, event).StatusCode)
+		test.eventRepo.AssertNumberOfCalls(t, "CreateEvent", 2)
+	})
+
+	t.Run("Should store an event id once within a batch", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 2
+		})).Return(int64(2), nil).Once()
+
+		withID := newEvent(projectID.String())
+		withID["EventID"] = "event-1"
+		otherID := newEvent(projectID.String())
+		otherID["EventID"] = "event-2"
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{withID, withID, otherID, otherID})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 2, result.Accepted)
+		assert.Equal(t, 2, result.Duplicates)
+		assert.Equal(t, entities.EventDuplicate, result.Results[1].Status)
+		assert.Equal(t, entities.EventDuplicate, result.Results[3].Status)
+	})
+
+	t.Run("Should mark batch events received by an earlier request as duplicates", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 1
+		})).Return(int64(1), nil).Once()
+
+		withID := newEvent(projectID.String())
+		withID["EventID"] = "event-1"
+		assert.Equal(t, fiber.StatusOK, sendEvent(t, test.app("/event", test.service.CreateEvent), withID).StatusCode)
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{withID, newEvent(projectID.String())})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, []string{entities.EventDuplicate, entities.EventAccepted}, []string{result.Results[0].Status, result.Results[1].Status})
+	})
+
+	t.Run("Should release every event id of the batch when the batch could not be stored", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.Anything).Return(int64(0), errors.New("connection refused")).Once()
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 2
+		})).Return(int64(2), nil).Once()
+		app := test.app("/batch", test.service.CreateEvents)
+
+		first := newEvent(projectID.String())
+		first["EventID"] = "event-1"
+		second := newEvent(projectID.String())
+		second["EventID"] = "event-2"
+
+		status, _ := sendBatch(t, app, []map[string]interface{}{first, second})
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		assert.False(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "event-1")))
+		assert.False(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "event-2")))
+
+		status, result := sendBatch(t, app, []map[string]interface{}{first, second})
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 2, result.Accepted)
+		assert.Equal(t, 0, result.Duplicates)
+	})
+}
+
+func TestApplyPrivacySignal(t *testing.T) {
+	optOut := false
+	optIn := true
+
+	tests := []struct {
+		name               string
+		policy             string
+		headers            map[string]string
+		consent            *bool
+		expectedSuppressed bool
+		expectedAnonymized bool
+	}{
+		{
+			name:    "Should ignore signals by default",
+			headers: map[string]string{"DNT": "1"},
+		},
+		{
+			name:    "Should ignore signals with the ignore policy",
+			policy:  entities.PrivacySignalIgnore,
+			headers: map[string]string{"DNT": "1", "Sec-GPC": "1"},
+			consent: &optOut,
+		},
+		{
+			name:               "Should suppress events with DNT",
+			policy:             entities.PrivacySignalReject,
+			headers:            map[string]string{"DNT": "1"},
+			expectedSuppressed: true,
+		},
+		{
+			name:               "Should suppress events with Sec-GPC",
+			policy:             entities.PrivacySignalReject,
+			headers:            map[string]string{"Sec-GPC": "1"},
+			expectedSuppressed: true,
+		},
+		{
+			name:               "Should suppress events without consent",
+			policy:             entities.PrivacySignalReject,
+			consent:            &optOut,
+			expectedSuppressed: true,
+		},
+		{
+			name:               "Should anonymize events with the anonymize policy",
+			policy:             entities.PrivacySignalAnonymize,
+			headers:            map[string]string{"Sec-GPC": "1"},
+			expectedSuppressed: true,
+			expectedAnonymized: true,
+		},
+		{
+			name:    "Should keep events with consent and no signal",
+			policy:  entities.PrivacySignalReject,
+			consent: &optIn,
+		},
+		{
+			name:    "Should keep events with signals other than 1",
+			policy:  entities.PrivacySignalReject,
+			headers: map[string]string{"DNT": "0", "Sec-GPC": "0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			input := dto.CreateEventInput{Consent: test.consent}
+			var suppressed bool
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				suppressed = e.service.applyPrivacySignal(c, &input, &gen.FindProjectSettingsRow{PrivacySignalPolicy: test.policy})
+				return nil
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+			_, err := app.Test(req)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedSuppressed, suppressed)
+			assert.Equal(t, test.expectedAnonymized, input.Anonymized)
+		})
+	}
+}
+
+func TestCreateEventPrivacySignal(t *testing.T) {
+	// sendSignal posts the event with the Do Not Track header.
+	sendSignal := func(t *testing.T, app *fiber.App, event map[string]interface{}) *http.Response {
+		body, _ := json.Marshal(event)
+		req := httptest.NewRequest(fiber.MethodPost, "/event", strings.NewReader(string(body)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderUserAgent, "Mozilla/5.0")
+		req.Header.Set("DNT", "1")
+
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		return res
+	}
+
+	t.Run("Should count and not store events rejected by the policy", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{PrivacySignalPolicy: entities.PrivacySignalReject})
+		test.projectRepo.On("IncrementSuppressed", mock.Anything, &gen.IncrementSuppressedEventsParams{
+			ID:               projectID,
+			UserID:           test.userID,
+			SuppressedEvents: 1,
+		}).Return(nil).Once()
+
+		res := sendSignal(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should count and store anonymized events without visitor fields", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{PrivacySignalPolicy: entities.PrivacySignalAnonymize})
+		test.projectRepo.On("IncrementSuppressed", mock.Anything, &gen.IncrementSuppressedEventsParams{
+			ID:               projectID,
+			UserID:           test.userID,
+			SuppressedEvents: 1,
+		}).Return(nil).Once()
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.IpAddr == nil &&
+				!payload.SessionID.Valid &&
+				!payload.UserAgent.Valid &&
+				!payload.DistinctID.Valid &&
+				!payload.AnonymousID.Valid &&
+				!payload.VisitorHash.Valid &&
+				payload.EventType == "click"
+		})).Return(nil).Once()
+
+		event := newEvent(projectID.String())
+		event["SessionID"] = "session-1"
+		event["DistinctID"] = "user-1"
+		event["AnonymousID"] = "anon-1"
+
+		res := sendSignal(t, test.app("/event", test.service.CreateEvent), event)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Should count the suppressed events of a batch once per project", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{PrivacySignalPolicy: entities.PrivacySignalReject})
+		test.projectRepo.On("IncrementSuppressed", mock.Anything, &gen.IncrementSuppressedEventsParams{
+			ID:               projectID,
+			UserID:           test.userID,
+			SuppressedEvents: 2,
+		}).Return(nil).Once()
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 1
+		})).Return(int64(1), nil).Once()
+
+		withoutConsent := newEvent(projectID.String())
+		withoutConsent["Consent"] = false
+		withConsent := newEvent(projectID.String())
+		withConsent["Consent"] = true
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{withoutConsent, withConsent, withoutConsent})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, []string{entities.EventSuppressed, entities.EventAccepted, entities.EventSuppressed}, []string{
+			result.Results[0].Status,
+			result.Results[1].Status,
+			result.Results[2].Status,
+		})
+	})
+}
+
+// sendIdentify posts the identify body and decodes the merged count.
+func sendIdentify(t *testing.T, app *fiber.App, body map[string]interface{}) (*http.Response, int64) {
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(fiber.MethodPost, "/identify", strings.NewReader(string(raw)))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	res, err := app.Test(req)
+	assert.NoError(t, err)
+
+	var result struct {
+		Merged int64 `json:"merged"`
+	}
+	if res.StatusCode == fiber.StatusOK {
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+	}
+	return res, result.Merged
+}
+
+func TestIdentify(t *testing.T) {
+	t.Run("Should store the alias and merge the anonymous events", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateIdentityAlias", mock.Anything, &gen.CreateIdentityAliasParams{
+			ProjectID:   projectID,
+			AnonymousID: "anon-1",
+			DistinctID:  "user-1",
+			UserID:      test.userID,
+		}).Return(nil).Once()
+		test.eventRepo.On("MergeAnonymousEvents", mock.Anything, &gen.MergeAnonymousEventsParams{
+			ProjectID:   projectID,
+			AnonymousID: pgtype.Text{String: "anon-1", Valid: true},
+			DistinctID:  pgtype.Text{String: "user-1", Valid: true},
+			UserID:      test.userID,
+		}).Return(int64(3), nil).Once()
+
+		res, merged := sendIdentify(t, test.app("/identify", test.service.Identify), map[string]interface{}{
+			"ProjectID":   projectID.String(),
+			"AnonymousID": "anon-1",
+			"DistinctID":  "user-1",
+		})
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.Equal(t, int64(3), merged)
+
+		// merged events change the project summary
+		assert.Len(t, test.service.WorkerPool.jobChan, 1)
+	})
+
+	t.Run("Should not queue an aggregation without merged events", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateIdentityAlias", mock.Anything, mock.Anything).Return(nil).Once()
+		test.eventRepo.On("MergeAnonymousEvents", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+
+		res, merged := sendIdentify(t, test.app("/identify", test.service.Identify), map[string]interface{}{
+			"ProjectID":   projectID.String(),
+			"AnonymousID": "anon-1",
+			"DistinctID":  "user-1",
+		})
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.Equal(t, int64(0), merged)
+		assert.Len(t, test.service.WorkerPool.jobChan, 0)
+	})
+
+	tests := []struct {
+		name           string
+		body           func(projectID uuid.UUID) map[string]interface{}
+		aliasErr       error
+		mergeErr       error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "Should reject a body without distinct id",
+			body: func(projectID uuid.UUID) map[string]interface{} {
+				return map[string]interface{}{"ProjectID": projectID.String(), "AnonymousID": "anon-1"}
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name: "Should reject a project of another user",
+			body: func(projectID uuid.UUID) map[string]interface{} {
+				return map[string]interface{}{"ProjectID": uuid.NewString(), "AnonymousID": "anon-1", "DistinctID": "user-1"}
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   entities.APIErrorNotFound,
+		},
+		{
+			name: "Should not merge events if the alias could not be stored",
+			body: func(projectID uuid.UUID) map[string]interface{} {
+				return map[string]interface{}{"ProjectID": projectID.String(), "AnonymousID": "anon-1", "DistinctID": "user-1"}
+			},
+			aliasErr:       errors.New("connection refused"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedCode:   entities.APIErrorInternal,
+		},
+		{
+			name: "Should not expose the error of a failed merge",
+			body: func(projectID uuid.UUID) map[string]interface{} {
+				return map[string]interface{}{"ProjectID": projectID.String(), "AnonymousID": "anon-1", "DistinctID": "user-1"}
+			},
+			mergeErr:       errors.New("connection refused"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedCode:   entities.APIErrorInternal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			projectID := e.project(gen.FindProjectSettingsRow{})
+			e.projectRepo.On("FindSettings", mock.Anything, mock.Anything).Return(gen.FindProjectSettingsRow{}, errors.New("no rows")).Maybe()
+			if test.aliasErr != nil || test.mergeErr != nil {
+				e.eventRepo.On("CreateIdentityAlias", mock.Anything, mock.Anything).Return(test.aliasErr).Once()
+			}
+			if test.mergeErr != nil {
+				e.eventRepo.On("MergeAnonymousEvents", mock.Anything, mock.Anything).Return(int64(0), test.mergeErr).Once()
+			}
+
+			res, _ := sendIdentify(t, e.app("/identify", e.service.Identify), test.body(projectID))
+
+			apiErr := decodeAPIError(t, res)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Equal(t, test.expectedCode, apiErr.Code)
+			assert.NotContains(t, apiErr.Error, "connection refused")
+			if test.aliasErr != nil {
+				e.eventRepo.AssertNotCalled(t, "MergeAnonymousEvents", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestResolveAnonymousID(t *testing.T) {
+	tests := []struct {
+		name                string
+		distinctID          string
+		alias               string
+		aliasErr            error
+		expectedDistinctID  string
+		expectedAnonymousID string
+	}{
+		{
+			name:                "Should store events of an identified anonymous id with its distinct id",
+			alias:               "user-1",
+			expectedDistinctID:  "user-1",
+			expectedAnonymousID: "anon-1",
+		},
+		{
+			name:                "Should keep the anonymous id alone if it was not identified",
+			aliasErr:            errors.New("no rows in result set"),
+			expectedAnonymousID: "anon-1",
+		},
+		{
+			name:                "Should keep the distinct id sent with the event",
+			distinctID:          "user-2",
+			expectedDistinctID:  "user-2",
+			expectedAnonymousID: "anon-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			projectID := e.project(gen.FindProjectSettingsRow{})
+			if test.distinctID == "" {
+				e.eventRepo.On("FindIdentityAlias", mock.Anything, &gen.FindIdentityAliasParams{
+					ProjectID:   projectID,
+					AnonymousID: "anon-1",
+				}).Return(test.alias, test.aliasErr).Once()
+			}
+			e.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+				return payload.DistinctID == pgtype.Text{String: test.expectedDistinctID, Valid: test.expectedDistinctID != ""} &&
+					payload.AnonymousID == pgtype.Text{String: test.expectedAnonymousID, Valid: true}
+			})).Return(nil).Once()
+
+			event := newEvent(projectID.String())
+			event["AnonymousID"] = "anon-1"
+			if test.distinctID != "" {
+				event["DistinctID"] = test.distinctID
+			}
+
+			res := sendEvent(t, e.app("/event", e.service.CreateEvent), event)
+
+			assert.Equal(t, fiber.StatusOK, res.StatusCode)
+			if test.distinctID != "" {
+				e.eventRepo.AssertNotCalled(t, "FindIdentityAlias", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// pixelApp routes the pixel handler behind a public key user, like GET /api/v1/pixel.gif.
+func (e *eventTest) pixelApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/pixel.gif", func(c *fiber.Ctx) error {
+		c.Locals("user", &gen.FindUserByPublicKeyRow{ID: e.userID})
+		return c.Next()
+	}, e.service.CreatePixelEvent)
+	return app
+}
+
+// assertPixel asserts the response is the uncached 1x1 GIF.
+func assertPixel(t *testing.T, res *http.Response) {
+	assert.Equal(t, "image/gif", res.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, "no-cache, no-store, must-revalidate, private", res.Header.Get(fiber.HeaderCacheControl))
+	assert.Equal(t, "no-cache", res.Header.Get(fiber.HeaderPragma))
+	assert.Equal(t, "0", res.Header.Get(fiber.HeaderExpires))
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.PIXEL_GIF, body)
+
+	config, err := gif.DecodeConfig(bytes.NewReader(body))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, config.Width)
+	assert.Equal(t, 1, config.Height)
+}
+
+func TestCreatePixelEvent(t *testing.T) {
+	t.Run("Should map the query parameters to the event", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.ProjectID == projectID &&
+				payload.UserID == test.userID &&
+				payload.EventType == "email_open" &&
+				payload.EventLabel.String == "newsletter" &&
+				payload.PageUrl.String == "https://example.com/mail" &&
+				payload.Referrer.String == "https://mail.example.com/" &&
+				payload.DistinctID.String == "user-1" &&
+				payload.AnonymousID.String == "anon-1"
+		})).Return(nil).Once()
+
+		query := url.Values{
+			"project_id":   {projectID.String()},
+			"event_type":   {"email_open"},
+			"event_id":     {"pixel-1"},
+			"label":        {"newsletter"},
+			"page_url":     {"https://example.com/mail"},
+			"referrer":     {"https://mail.example.com/"},
+			"distinct_id":  {"user-1"},
+			"anonymous_id": {"anon-1"},
+		}
+		res, err := test.pixelApp().Test(httptest.NewRequest(fiber.MethodGet, "/pixel.gif?"+query.Encode(), nil))
+		assert.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assertPixel(t, res)
+		assert.True(t, test.redis.Exists(configs.CACHE_EVENT_ID(projectID, "pixel-1")))
+	})
+
+	t.Run("Should use the Referer header without a page url", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.PageUrl.String == "https://example.com/noscript"
+		})).Return(nil).Once()
+
+		req := httptest.NewRequest(fiber.MethodGet, "/pixel.gif?event_type=page_view&project_id="+projectID.String(), nil)
+		req.Header.Set(fiber.HeaderReferer, "https://example.com/noscript")
+
+		res, err := test.pixelApp().Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assertPixel(t, res)
+	})
+
+	t.Run("Should respond with the GIF and the error status of a rejected event", func(t *testing.T) {
+		test := initEventTest(t)
+		test.projectRepo.On("FindSettings", mock.Anything, mock.Anything).Return(gen.FindProjectSettingsRow{}, errors.New("no rows")).Once()
+
+		invalid, err := test.pixelApp().Test(httptest.NewRequest(fiber.MethodGet, "/pixel.gif?event_type=page_view", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, invalid.StatusCode)
+		assertPixel(t, invalid)
+
+		notFound, err := test.pixelApp().Test(httptest.NewRequest(fiber.MethodGet, "/pixel.gif?event_type=page_view&project_id="+uuid.NewString(), nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, notFound.StatusCode)
+		assertPixel(t, notFound)
+
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+}
+
+// resetVisitorSalt drops the in-memory salt so the next hash reads the salt from redis.
+func resetVisitorSalt() {
+	dailySalt.Lock()
+	defer dailySalt.Unlock()
+	dailySalt.day = ""
+	dailySalt.salt = nil
+}
+
+func TestHashVisitor(t *testing.T) {
+	projectID := uuid.New()
+	morning := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	evening := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Should return the same hash within a day", func(t *testing.T) {
+		resetVisitorSalt()
+		test := initEventTest(t)
+
+		hash := test.service.hashVisitor(projectID, "203.0.113.7", morning)
+
+		assert.Len(t, hash, 64)
+		assert.NotContains(t, hash, "203.0.113.7")
+		assert.Equal(t, hash, test.service.hashVisitor(projectID, "203.0.113.7", evening))
+		assert.NotEqual(t, hash, test.service.hashVisitor(projectID, "203.0.113.8", evening))
+		assert.NotEqual(t, hash, test.service.hashVisitor(uuid.New(), "203.0.113.7", evening))
+	})
+
+	t.Run("Should return another hash the next day", func(t *testing.T) {
+		resetVisitorSalt()
+		test := initEventTest(t)
+
+		today := test.service.hashVisitor(projectID, "203.0.113.7", morning)
+		tomorrow := test.service.hashVisitor(projectID, "203.0.113.7", nextDay)
+
+		assert.NotEqual(t, today, tomorrow)
+		first, err := test.redis.Get(configs.CACHE_VISITOR_SALT("2024-03-01"))
+		assert.NoError(t, err)
+		second, err := test.redis.Get(configs.CACHE_VISITOR_SALT("2024-03-02"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("Should share the salt of the day between instances and let it expire", func(t *testing.T) {
+		resetVisitorSalt()
+		test := initEventTest(t)
+
+		hash := test.service.hashVisitor(projectID, "203.0.113.7", morning)
+
+		// another instance starts without the salt in memory
+		resetVisitorSalt()
+		assert.Equal(t, hash, test.service.hashVisitor(projectID, "203.0.113.7", evening))
+
+		ttl := test.redis.TTL(configs.CACHE_VISITOR_SALT("2024-03-01"))
+		assert.True(t, ttl > 0 && ttl <= constants.VISITOR_SALT_TTL, ttl)
+	})
+
+	t.Run("Should not store the IP in hash mode", func(t *testing.T) {
+		resetVisitorSalt()
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{IpMode: entities.IPModeHash})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.IpAddr == nil &&
+				payload.VisitorHash.Valid &&
+				payload.VisitorHash.String == test.service.hashVisitor(projectID, "0.0.0.0", time.Now())
+		})).Return(nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+}
+
+func TestCorrectFiredAt(t *testing.T) {
+	receivedAt := time.Now().Truncate(time.Second)
+	earliest := receivedAt.Add(-constants.EVENT_MAX_AGE)
+	latest := receivedAt.Add(constants.EVENT_MAX_FUTURE)
+
+	tests := []struct {
+		name                  string
+		policy                string
+		firedAt               time.Time
+		sentAt                time.Time
+		clientFiredAt         string
+		expectedFiredAt       time.Time
+		expectedClientFiredAt bool
+		expectErr             bool
+	}{
+		{
+			name:            "Should keep a timestamp within the window",
+			policy:          entities.TimestampPolicyClamp,
+			firedAt:         receivedAt.Add(-time.Minute),
+			expectedFiredAt: receivedAt.Add(-time.Minute),
+		},
+		{
+			name:            "Should not keep a client value when FiredAt is not changed",
+			policy:          entities.TimestampPolicyClamp,
+			firedAt:         receivedAt.Add(-time.Minute),
+			clientFiredAt:   receivedAt.Add(-time.Hour).Format(time.RFC3339),
+			expectedFiredAt: receivedAt.Add(-time.Minute),
+		},
+		{
+			name:                  "Should correct FiredAt by the offset of the client clock",
+			policy:                entities.TimestampPolicyClamp,
+			firedAt:               receivedAt.Add(-2*time.Hour - time.Minute),
+			sentAt:                receivedAt.Add(-2 * time.Hour),
+			expectedFiredAt:       receivedAt.Add(-time.Minute),
+			expectedClientFiredAt: true,
+		},
+		{
+			name:                  "Should clamp a timestamp older than the maximum age",
+			policy:                entities.TimestampPolicyClamp,
+			firedAt:               earliest.Add(-time.Hour),
+			expectedFiredAt:       earliest,
+			expectedClientFiredAt: true,
+		},
+		{
+			name:                  "Should clamp a timestamp too far in the future",
+			policy:                entities.TimestampPolicyClamp,
+			firedAt:               latest.Add(time.Hour),
+			expectedFiredAt:       latest,
+			expectedClientFiredAt: true,
+		},
+		{
+			name:      "Should reject a timestamp older than the maximum age",
+			policy:    entities.TimestampPolicyReject,
+			firedAt:   earliest.Add(-time.Hour),
+			expectErr: true,
+		},
+		{
+			name:      "Should reject a timestamp too far in the future",
+			policy:    entities.TimestampPolicyReject,
+			firedAt:   latest.Add(time.Hour),
+			expectErr: true,
+		},
+		{
+			name:                  "Should accept a corrected timestamp within the window with the reject policy",
+			policy:                entities.TimestampPolicyReject,
+			firedAt:               latest.Add(time.Hour),
+			sentAt:                receivedAt.Add(time.Hour),
+			expectedFiredAt:       latest,
+			expectedClientFiredAt: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			input := &dto.CreateEventInput{
+				FiredAt:       test.firedAt.Format(time.RFC3339),
+				ClientFiredAt: test.clientFiredAt,
+			}
+			if !test.sentAt.IsZero() {
+				input.SentAt = test.sentAt.Format(time.RFC3339)
+			}
+
+			err := e.service.correctFiredAt(input, &gen.FindProjectSettingsRow{TimestampPolicy: test.policy}, receivedAt)
+
+			if test.expectErr {
+				assert.ErrorIs(t, err, errTimestampOutOfRange)
+				return
+			}
+			assert.NoError(t, err)
+
+			firedAt, err := time.Parse(time.RFC3339Nano, input.FiredAt)
+			assert.NoError(t, err)
+			assert.True(t, test.expectedFiredAt.Equal(firedAt), "expected %s, got %s", test.expectedFiredAt, firedAt)
+			if test.expectedClientFiredAt {
+				assert.Equal(t, test.firedAt.Format(time.RFC3339), input.ClientFiredAt)
+			} else {
+				assert.Empty(t, input.ClientFiredAt)
+			}
+		})
+	}
+
+	t.Run("Should not store a client fired at sent in a form body", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{TimestampPolicy: entities.TimestampPolicyClamp})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return !payload.ClientFiredAt.Valid
+		})).Return(nil).Once()
+
+		values := formEvent(projectID)
+		values.Set("ClientFiredAt", time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+		res := sendForm(t, test.app("/event", test.service.CreateEvent), values)
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Should reject events out of the window of a project with the reject policy", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{TimestampPolicy: entities.TimestampPolicyReject})
+
+		values := formEvent(projectID)
+		values.Set("FiredAt", time.Now().Add(-constants.EVENT_MAX_AGE-time.Hour).Format(time.RFC3339))
+
+		res := sendForm(t, test.app("/event", test.service.CreateEvent), values)
+
+		assert.Equal(t, fiber.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, errTimestampOutOfRange.Error(), decodeAPIError(t, res).Error)
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateEventRateLimits(t *testing.T) {
+	limits := gen.FindProjectSettingsRow{RateLimit: 10, RateLimitBurst: 20}
+
+	t.Run("Should set the headers of the most restrictive bucket", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(limits)
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.Equal(t, "20", res.Header.Get(constants.HEADER_RATE_LIMIT_LIMIT))
+		assert.Equal(t, "19", res.Header.Get(constants.HEADER_RATE_LIMIT_REMAINING))
+		assert.Equal(t, "1", res.Header.Get(constants.HEADER_RATE_LIMIT_RESET))
+		assert.Empty(t, res.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("Should reject events over the project limit with Retry-After", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(limits)
+		test.emptyBucket(configs.CACHE_RATE_LIMIT_PROJECT(projectID))
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "20", res.Header.Get(constants.HEADER_RATE_LIMIT_LIMIT))
+		assert.Equal(t, "0", res.Header.Get(constants.HEADER_RATE_LIMIT_REMAINING))
+		assert.Equal(t, "1", res.Header.Get(fiber.HeaderRetryAfter))
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject events over the public key limit with Retry-After", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(limits)
+		test.emptyBucket(configs.CACHE_RATE_LIMIT_KEY(test.userID))
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, strconv.Itoa(constants.PUBLIC_KEY_RATE_BURST), res.Header.Get(constants.HEADER_RATE_LIMIT_LIMIT))
+		assert.Equal(t, "0", res.Header.Get(constants.HEADER_RATE_LIMIT_REMAINING))
+		assert.Equal(t, "1", res.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("Should not limit projects without limits by default", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.False(t, test.redis.Exists(configs.CACHE_RATE_LIMIT_PROJECT(projectID)))
+		assert.Equal(t, strconv.Itoa(constants.PUBLIC_KEY_RATE_BURST), res.Header.Get(constants.HEADER_RATE_LIMIT_LIMIT))
+	})
+
+	t.Run("Should rate limit the events of a batch above the granted tokens", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{RateLimit: 1, RateLimitBurst: 3})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 3
+		})).Return(int64(3), nil).Once()
+
+		events := make([]map[string]interface{}, 5)
+		for i := range events {
+			events[i] = newEvent(projectID.String())
+		}
+		body, _ := json.Marshal(map[string]interface{}{"Events": events})
+		req := httptest.NewRequest(fiber.MethodPost, "/batch", strings.NewReader(string(body)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		res, err := test.app("/batch", test.service.CreateEvents).Test(req)
+		assert.NoError(t, err)
+
+		var result batchResponse
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.Equal(t, 3, result.Accepted)
+		assert.Equal(t, 2, result.RateLimited)
+		assert.Equal(t, entities.EventRateLimited, result.Results[4].Status)
+		assert.Equal(t, "3", res.Header.Get(constants.HEADER_RATE_LIMIT_LIMIT))
+		assert.Equal(t, "0", res.Header.Get(constants.HEADER_RATE_LIMIT_REMAINING))
+		assert.Equal(t, "1", res.Header.Get(fiber.HeaderRetryAfter))
+	})
+}
+
+func TestCreateEventQuotas(t *testing.T) {
+	month := time.Now().Format("2006-01")
+
+	t.Run("Should store events under the project quota and count them", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{MonthlyQuota: 5})
+		test.eventRepo.On("CountProjectMonthlyEvents", mock.Anything, &gen.CountProjectMonthlyEventsParams{
+			ProjectID: projectID,
+			UserID:    test.userID,
+		}).Return(int64(3), nil).Once()
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		count, err := test.redis.Get(configs.CACHE_PROJECT_MONTHLY_EVENTS(projectID, month))
+		assert.NoError(t, err)
+		assert.Equal(t, "4", count)
+	})
+
+	t.Run("Should reject events over the project quota until the next month", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{MonthlyQuota: 5})
+		test.eventRepo.On("CountProjectMonthlyEvents", mock.Anything, mock.Anything).Return(int64(5), nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		retryAfter, err := strconv.Atoi(res.Header.Get(fiber.HeaderRetryAfter))
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Until(nextMonth()).Seconds(), retryAfter, 5)
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject events over the account quota", func(t *testing.T) {
+		quota := constants.MONTHLY_EVENT_QUOTA
+		constants.MONTHLY_EVENT_QUOTA = 10
+		t.Cleanup(func() { constants.MONTHLY_EVENT_QUOTA = quota })
+
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		assert.NoError(t, test.redis.Set(configs.CACHE_MONTHLY_EVENTS(test.userID, month), "10"))
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, res.StatusCode)
+		assert.NotEmpty(t, res.Header.Get(fiber.HeaderRetryAfter))
+		test.eventRepo.AssertNotCalled(t, "CountUserMonthlyEvents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should not count events without quotas", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		res := sendEvent(t, test.app("/event", test.service.CreateEvent), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+		assert.False(t, test.redis.Exists(configs.CACHE_MONTHLY_EVENTS(test.userID, month)))
+		assert.False(t, test.redis.Exists(configs.CACHE_PROJECT_MONTHLY_EVENTS(projectID, month)))
+	})
+}
+
+func TestCreateEventsSigned(t *testing.T) {
+	const secret = "signing-secret"
+
+	t.Run("Should trust the events of a signed batch of one project", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{
+			SigningSecret: pgtype.Text{String: secret, Valid: true},
+		})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 2 && payloads[0].Trusted && payloads[1].Trusted
+		})).Return(int64(2), nil).Once()
+
+		status, result := sendSignedBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{
+			newEvent(projectID.String()),
+			newEvent(projectID.String()),
+		}, secret)
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 2, result.Accepted)
+	})
+
+	t.Run("Should reject a signed batch with events of several projects", func(t *testing.T) {
+		test := initEventTest(t)
+		first := test.project(gen.FindProjectSettingsRow{
+			SigningSecret: pgtype.Text{String: secret, Valid: true},
+		})
+		second := test.project(gen.FindProjectSettingsRow{
+			SigningSecret: pgtype.Text{String: secret, Valid: true},
+		})
+
+		status, result := sendSignedBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{
+			newEvent(first.String()),
+			newEvent(second.String()),
+		}, secret)
+
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Equal(t, errMixedSignedBatch.Error(), result.Error)
+		test.eventRepo.AssertNotCalled(t, "CreateEvents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should accept an unsigned batch with events of several projects", func(t *testing.T) {
+		test := initEventTest(t)
+		first := test.project(gen.FindProjectSettingsRow{})
+		second := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 2 && !payloads[0].Trusted && !payloads[1].Trusted
+		})).Return(int64(2), nil).Once()
+
+		status, result := sendBatch(t, test.app("/batch", test.service.CreateEvents), []map[string]interface{}{
+			newEvent(first.String()),
+			newEvent(second.String()),
+		})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 2, result.Accepted)
+	})
+}
+
+// serverApp routes the handler behind a private key user, like the /api/v1/server routes.
+func (e *eventTest) serverApp(path string, handler fiber.Handler, keyProjectID uuid.UUID) *fiber.App {
+	app := fiber.New()
+	app.Post(path, func(c *fiber.Ctx) error {
+		c.Locals("user", &gen.FindUserByPrivateKeyRow{
+			ID:        e.userID,
+			ProjectID: pgtype.UUID{Bytes: keyProjectID, Valid: keyProjectID != uuid.Nil},
+		})
+		return c.Next()
+	}, handler)
+	return app
+}
+
+func TestCreateEventPrivateKey(t *testing.T) {
+	t.Run("Should store the event of a private key for its user", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvent", mock.Anything, mock.MatchedBy(func(payload *gen.CreateEventParams) bool {
+			return payload.UserID == test.userID && payload.ProjectID == projectID
+		})).Return(nil).Once()
+
+		res := sendEvent(t, test.serverApp("/event", test.service.CreateEvent, projectID), newEvent(projectID.String()))
+
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Should reject an event of another project than the one of the key", func(t *testing.T) {
+		test := initEventTest(t)
+		projectID := test.project(gen.FindProjectSettingsRow{})
+
+		res := sendEvent(t, test.serverApp("/event", test.service.CreateEvent, uuid.New()), newEvent(projectID.String()))
+
+		apiErr := decodeAPIError(t, res)
+		assert.Equal(t, fiber.StatusForbidden, res.StatusCode)
+		assert.Equal(t, entities.APIErrorForbidden, apiErr.Code)
+		test.eventRepo.AssertNotCalled(t, "CreateEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should reject batch events of another project than the one of the key", func(t *testing.T) {
+		test := initEventTest(t)
+		keyProjectID := test.project(gen.FindProjectSettingsRow{})
+		otherID := test.project(gen.FindProjectSettingsRow{})
+		test.eventRepo.On("CreateEvents", mock.Anything, mock.MatchedBy(func(payloads []gen.CreateEventsParams) bool {
+			return len(payloads) == 1 && payloads[0].ProjectID == keyProjectID
+		})).Return(int64(1), nil).Once()
+
+		status, result := sendBatch(t, test.serverApp("/batch", test.service.CreateEvents, keyProjectID), []map[string]interface{}{
+			newEvent(keyProjectID.String()),
+			newEvent(otherID.String()),
+		})
+
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, 1, result.Accepted)
+		assert.Equal(t, 1, result.Rejected)
+		assert.Equal(t, entities.EventRejected, result.Results[1].Status)
+		assert.Equal(t, errKeyProjectForbidden.Error(), result.Results[1].Error)
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "signing-secret"
+	body := []byte(`{"EventType":"purchase"}`)
+
+	tests := []struct {
+		name            string
+		secret          string
+		signWith        string
+		timestamp       time.Time
+		signature       string
+		sendTwice       bool
+		expectedTrusted bool
+		expectedError   string
+	}{
+		{
+			name:            "Should not trust unsigned requests",
+			secret:          secret,
+			expectedTrusted: false,
+		},
+		{
+			name:            "Should trust a valid signature",
+			secret:          secret,
+			signWith:        secret,
+			timestamp:       time.Now(),
+			expectedTrusted: true,
+		},
+		{
+			name:          "Should reject a signature of another secret",
+			secret:        secret,
+			signWith:      "another-secret",
+			timestamp:     time.Now(),
+			expectedError: errInvalidSignature.Error(),
+		},
+		{
+			name:          "Should reject a signature that is not hex encoded",
+			secret:        secret,
+			timestamp:     time.Now(),
+			signature:     "not-a-signature",
+			expectedError: errInvalidSignature.Error(),
+		},
+		{
+			name:          "Should reject a stale timestamp",
+			secret:        secret,
+			signWith:      secret,
+			timestamp:     time.Now().Add(-constants.SIGNATURE_TOLERANCE - time.Minute),
+			expectedError: "timestamp is outside of the allowed window",
+		},
+		{
+			name:          "Should reject a timestamp in the future",
+			secret:        secret,
+			signWith:      secret,
+			timestamp:     time.Now().Add(constants.SIGNATURE_TOLERANCE + time.Minute),
+			expectedError: "timestamp is outside of the allowed window",
+		},
+		{
+			name:          "Should reject a replayed request",
+			secret:        secret,
+			signWith:      secret,
+			timestamp:     time.Now(),
+			sendTwice:     true,
+			expectedError: "request was already received",
+		},
+		{
+			name:          "Should reject signed requests to a project without secret",
+			signWith:      secret,
+			timestamp:     time.Now(),
+			expectedError: "project has no signing secret",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			settings := &gen.FindProjectSettingsRow{
+				ID:            uuid.New(),
+				SigningSecret: pgtype.Text{String: test.secret, Valid: test.secret != ""},
+			}
+
+			app := fiber.New()
+			app.Post("/", func(c *fiber.Ctx) error {
+				trusted, err := e.service.verifySignature(c, settings)
+				result := fiber.Map{"trusted": trusted}
+				if err != nil {
+					result["error"] = err.Error()
+				}
+				return c.JSON(result)
+			})
+
+			send := func() (bool, string) {
+				req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(string(body)))
+				if test.signWith != "" {
+					sign(req, body, test.signWith, test.timestamp)
+				}
+				if test.signature != "" {
+					req.Header.Set(constants.HEADER_SIGNATURE_TIMESTAMP, strconv.FormatInt(test.timestamp.Unix(), 10))
+					req.Header.Set(constants.HEADER_SIGNATURE, test.signature)
+				}
+
+				res, err := app.Test(req)
+				assert.NoError(t, err)
+
+				var result struct {
+					Trusted bool   `json:"trusted"`
+					Error   string `json:"error"`
+				}
+				assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+				return result.Trusted, result.Error
+			}
+
+			if test.sendTwice {
+				trusted, err := send()
+				assert.True(t, trusted)
+				assert.Empty(t, err)
+			}
+
+			trusted, err := send()
+			assert.Equal(t, test.expectedTrusted, trusted)
+			if test.expectedError == "" {
+				assert.Empty(t, err)
+			} else {
+				assert.Contains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetEventPropertyBreakdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit          string
+		expectedStatus int
+		expectedLimit  int32
+	}{
+		{
+			name:           "Should use the default limit",
+			limit:          "",
+			expectedStatus: fiber.StatusOK,
+			expectedLimit:  100,
+		},
+		{
+			name:           "Should accept the maximum page size",
+			limit:          strconv.Itoa(constants.MAX_EVENTS_PAGE_SIZE),
+			expectedStatus: fiber.StatusOK,
+			expectedLimit:  int32(constants.MAX_EVENTS_PAGE_SIZE),
+		},
+		{
+			name:           "Should reject a limit of zero",
+			limit:          "0",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Should reject a negative limit",
+			limit:          "-10",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Should reject a limit over the maximum page size",
+			limit:          strconv.Itoa(constants.MAX_EVENTS_PAGE_SIZE + 1),
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "Should reject a limit that is not a number",
+			limit:          "ten",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			if test.expectedStatus == fiber.StatusOK {
+				e.eventRepo.On("GetEventPropertyBreakdown", mock.Anything, mock.MatchedBy(func(input *gen.GetEventPropertyBreakdownParams) bool {
+					return input.LimitCount == test.expectedLimit && input.PropertyKey == "plan"
+				})).Return([]gen.GetEventPropertyBreakdownRow{}, nil).Once()
+			}
+
+			path := "/properties/plan"
+			if test.limit != "" {
+				path += "?limit=" + test.limit
+			}
+			res, err := e.readApp("/properties/:key", e.service.GetEventPropertyBreakdown).Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func decodeAPIError(t *testing.T, res *http.Response) entities.APIError {
+	var apiErr entities.APIError
+	assert.Equal(t, fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&apiErr))
+	return apiErr
+}
+
+func TestCreateEventErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(e *eventTest, projectID uuid.UUID)
+		body           func(projectID uuid.UUID) string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "Should reject a body that is not JSON",
+			body: func(projectID uuid.UUID) string {
+				return "{"
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name: "Should reject an invalid event",
+			body: func(projectID uuid.UUID) string {
+				return `{"ProjectID":"` + projectID.String() + `"}`
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name: "Should reject events of an unknown project",
+			setup: func(e *eventTest, projectID uuid.UUID) {
+				e.projectRepo.On("FindSettings", mock.Anything, mock.Anything).Return(gen.FindProjectSettingsRow{}, errors.New("no rows")).Once()
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   entities.APIErrorNotFound,
+		},
+		{
+			name: "Should reject events over the rate limit",
+			setup: func(e *eventTest, projectID uuid.UUID) {
+				e.emptyBucket(configs.CACHE_RATE_LIMIT_KEY(e.userID))
+			},
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedCode:   entities.APIErrorRateLimited,
+		},
+		{
+			name: "Should reject events over the monthly quota",
+			setup: func(e *eventTest, projectID uuid.UUID) {
+				e.project(gen.FindProjectSettingsRow{ID: projectID, MonthlyQuota: 1})
+				e.eventRepo.On("CountProjectMonthlyEvents", mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+			},
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedCode:   entities.APIErrorQuotaExceeded,
+		},
+		{
+			name: "Should reject events with an invalid signature",
+			setup: func(e *eventTest, projectID uuid.UUID) {
+				e.project(gen.FindProjectSettingsRow{ID: projectID})
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedCode:   entities.APIErrorInvalidSignature,
+		},
+		{
+			name: "Should not expose the error of a failed insert",
+			setup: func(e *eventTest, projectID uuid.UUID) {
+				e.project(gen.FindProjectSettingsRow{ID: projectID})
+				e.eventRepo.On("CreateEvent", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedCode:   entities.APIErrorInternal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			projectID := uuid.New()
+			if test.setup != nil {
+				test.setup(e, projectID)
+			}
+
+			body := `{"ProjectID":"` + projectID.String() + `","EventType":"click","FiredAt":"` + time.Now().Format(time.RFC3339) + `"}`
+			if test.body != nil {
+				body = test.body(projectID)
+			}
+			req := httptest.NewRequest(fiber.MethodPost, "/event", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if test.expectedCode == entities.APIErrorInvalidSignature {
+				req.Header.Set(constants.HEADER_SIGNATURE, "signature")
+			}
+
+			res, err := e.app("/event", e.service.CreateEvent).Test(req)
+			assert.NoError(t, err)
+
+			apiErr := decodeAPIError(t, res)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Equal(t, test.expectedCode, apiErr.Code)
+			assert.NotEmpty(t, apiErr.Error)
+			assert.NotContains(t, apiErr.Error, "connection refused")
+		})
+	}
+}
+
+func TestGetEventsErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		keyProjectID   uuid.UUID
+		repoErr        error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Should reject an unknown interval",
+			query:          "interval=last_decade",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject a limit over the maximum page size",
+			query:          "limit=" + strconv.Itoa(constants.MAX_EVENTS_PAGE_SIZE+1),
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject an invalid project id",
+			query:          "project_id=not-a-uuid",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject an unknown order",
+			query:          "order=random",
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   entities.APIErrorBadRequest,
+		},
+		{
+			name:           "Should reject another project than the one of the key",
+			query:          "project_id=" + uuid.NewString(),
+			keyProjectID:   uuid.New(),
+			expectedStatus: fiber.StatusForbidden,
+			expectedCode:   entities.APIErrorForbidden,
+		},
+		{
+			name:           "Should not expose the error of a failed query",
+			repoErr:        errors.New("connection refused"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedCode:   entities.APIErrorInternal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := initEventTest(t)
+			if test.repoErr != nil {
+				e.eventRepo.On("GetEvents", mock.Anything, mock.Anything).Return(nil, test.repoErr).Once()
+			}
+
+			app := fiber.New()
+			app.Get("/events", func(c *fiber.Ctx) error {
+				c.Locals("user", &gen.FindUserByPrivateKeyRow{
+					ID:        e.userID,
+					ProjectID: pgtype.UUID{Bytes: test.keyProjectID, Valid: test.keyProjectID != uuid.Nil},
+				})
+				return c.Next()
+			}, e.service.GetEvents)
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/events?"+test.query, nil))
+			assert.NoError(t, err)
+
+			apiErr := decodeAPIError(t, res)
+			assert.Equal(t, test.expectedStatus, res.StatusCode)
+			assert.Equal(t, test.expectedCode, apiErr.Code)
+			assert.NotContains(t, apiErr.Error, "connection refused")
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/dto"
+	"github.com/hubkudev/sentinel/internal/entities"
+)
+
+// EventProcessor is a step of the ingestion pipeline, it enriches the event or drops it.
+// Processors run in the order they are registered and are enabled per project by name.
+type EventProcessor interface {
+	// Name identifies the processor in the project settings.
+	Name() string
+	// Process returns the modified event, or nil to drop it.
+	Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput
+}
+
+// ProcessorContext is the data of the request available to the processors.
+// The event's IPAddr and UserAgent are already set from the request when the chain runs.
+type ProcessorContext struct {
+	UserID   uuid.UUID
+	Settings *gen.FindProjectSettingsRow
+}
+
+// ProcessorChain runs the registered processors enabled by the project.
+type ProcessorChain struct {
+	processors []EventProcessor
+}
+
+func InitProcessorChain(processors ...EventProcessor) *ProcessorChain {
+	chain := &ProcessorChain{}
+	for _, processor := range processors {
+		if err := chain.Register(processor); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return chain
+}
+
+// BuiltinProcessors returns the processors shipped with sentinel, in the order they run.
+func BuiltinProcessors(utilService UtilService) []EventProcessor {
+	return []EventProcessor{
+		&BotFilterProcessor{UtilService: utilService},
+		&SamplingProcessor{},
+		&GeoIPProcessor{UtilService: utilService},
+		&UserAgentProcessor{UtilService: utilService},
+		&RedactionProcessor{UtilService: utilService},
+	}
+}
+
+// Register appends the processor to the end of the chain, names must be unique.
+func (c *ProcessorChain) Register(processor EventProcessor) error {
+	if slices.Contains(c.Names(), processor.Name()) {
+		return fmt.Errorf("processor %s is already registered", processor.Name())
+	}
+	c.processors = append(c.processors, processor)
+	return nil
+}
+
+// Names returns the names of the registered processors in the order they run.
+func (c *ProcessorChain) Names() []string {
+	names := make([]string, 0, len(c.processors))
+	for _, processor := range c.processors {
+		names = append(names, processor.Name())
+	}
+	return names
+}
+
+// Run passes the event through the processors enabled by the project,
+// returns nil as soon as a processor drops the event.
+func (c *ProcessorChain) Run(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	for _, processor := range c.processors {
+		if !slices.Contains(ctx.Settings.Processors, processor.Name()) {
+			continue
+		}
+
+		event = processor.Process(ctx, event)
+		if event == nil {
+			return nil
+		}
+	}
+	return event
+}
+
+// BotFilterProcessor flags bot traffic from the User-Agent and the client IP,
+// and drops it if the project's bot policy says so.
+type BotFilterProcessor struct {
+	UtilService UtilService
+}
+
+func (p *BotFilterProcessor) Name() string {
+	return "bot_filter"
+}
+
+func (p *BotFilterProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	// signed events are sent by the project's own backend, its http client is not a crawler
+	if event.Trusted {
+		return event
+	}
+
+	event.IsBot = p.UtilService.IsBot(event.UserAgent, event.IPAddr)
+	if event.IsBot && ctx.Settings.BotPolicy == entities.BotPolicyDrop {
+		return nil
+	}
+	return event
+}
+
+// SamplingProcessor keeps a share of the events of each type by the project's sample rules.
+// Sampling is deterministic by session, falling back to the distinct and anonymous id,
+// so the events of a kept session are kept together.
+type SamplingProcessor struct{}
+
+func (p *SamplingProcessor) Name() string {
+	return "sampling"
+}
+
+func (p *SamplingProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	rate := sampleRate(ctx.Settings.SampleRules, event.EventType)
+	if rate >= 1 {
+		event.SampleRate = 1
+		return event
+	}
+
+	var bucket float64
+	if key := sampleKey(event); key != "" {
+		bucket = sampleBucket(ctx.Settings.ID.String() + ":" + key)
+	} else {
+		bucket = rand.Float64()
+	}
+	if bucket >= rate {
+		return nil
+	}
+
+	event.SampleRate = rate
+	return event
+}
+
+// sampleRate returns the rate of the event type from rules such as scroll=0.1,
+// the * rule applies to the other types. Types without a rule are kept.
+func sampleRate(rules []string, eventType string) float64 {
+	rate := 1.0
+	for _, rule := range rules {
+		name, value, _ := strings.Cut(rule, "=")
+		if name != eventType && name != "*" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		if name == eventType {
+			return parsed
+		}
+		rate = parsed
+	}
+	return rate
+}
+
+// sampleKey returns the id the event is sampled by, empty if the event has none.
+func sampleKey(event *dto.CreateEventInput) string {
+	for _, key := range []string{event.SessionID, event.DistinctID, event.AnonymousID} {
+		if key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// sampleBucket maps the key to a stable number in [0, 1).
+func sampleBucket(key string) float64 {
+	sum := sha256.Sum256([]byte(key))
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
+
+// GeoIPProcessor looks up the client's location from the full IP,
+// before the IP is anonymized according to the project's settings.
+type GeoIPProcessor struct {
+	UtilService UtilService
+}
+
+func (p *GeoIPProcessor) Name() string {
+	return "geoip"
+}
+
+func (p *GeoIPProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	loc := p.UtilService.LookupIP(event.IPAddr)
+	if loc == nil {
+		return event
+	}
+
+	event.Country = loc.Country.Names["en"]
+	event.Region = loc.Continent.Names["en"]
+	if !ctx.Settings.GeoCountryOnly {
+		event.City = loc.City.Names["en"]
+	}
+	return event
+}
+
+// UserAgentProcessor parses the browser, OS and device type from the User-Agent.
+type UserAgentProcessor struct {
+	UtilService UtilService
+}
+
+func (p *UserAgentProcessor) Name() string {
+	return "user_agent"
+}
+
+func (p *UserAgentProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	// parsed values take precedence, client values are only kept as a fallback
+	// since they are sent in inconsistent formats.
+	ua := p.UtilService.ParseUserAgent(event.UserAgent)
+	if ua.BrowserName != "" {
+		event.BrowserName = ua.BrowserName
+	}
+	if ua.DeviceType != "" {
+		event.DeviceType = ua.DeviceType
+	}
+	event.BrowserVersion = ua.BrowserVersion
+	event.OSName = ua.OSName
+	event.OSVersion = ua.OSVersion
+	return event
+}
+
+// customRedactPatterns caches the compiled custom redaction patterns by their source,
+// patterns are validated when the project settings are saved.
+var customRedactPatterns sync.Map
+
+// RedactionProcessor masks personal data in the label, URLs, element path and string properties
+// with the project's redaction rules, the number of masked values is kept in the event's Redactions.
+type RedactionProcessor struct {
+	UtilService UtilService
+}
+
+func (p *RedactionProcessor) Name() string {
+	return "redaction"
+}
+
+func (p *RedactionProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	settings := ctx.Settings
+	if len(settings.RedactDetectors) == 0 && len(settings.RedactPatterns) == 0 {
+		return event
+	}
+
+	rules := entities.RedactionRules{Detectors: settings.RedactDetectors}
+	for _, pattern := range settings.RedactPatterns {
+		compiled, ok := customRedactPatterns.Load(pattern)
+		if !ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				log.Println("Error compiling redaction pattern:", err)
+				continue
+			}
+			compiled, _ = customRedactPatterns.LoadOrStore(pattern, re)
+		}
+		rules.Patterns = append(rules.Patterns, compiled.(*regexp.Regexp))
+	}
+
+	redact := func(value string) string {
+		masked, count := p.UtilService.Redact(value, rules)
+		event.Redactions += count
+		return masked
+	}
+
+	event.EventLabel = redact(event.EventLabel)
+	event.PageURL = redact(event.PageURL)
+	event.Referrer = redact(event.Referrer)
+	event.ElementPath = redact(event.ElementPath)
+	for key, value := range event.Properties {
+		event.Properties[key] = redactProperty(value, redact)
+	}
+	return event
+}
+
+// redactProperty masks the string values of a property, including nested objects and arrays.
+func redactProperty(value interface{}, redact func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redact(v)
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = redactProperty(nested, redact)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactProperty(nested, redact)
+		}
+	}
+	return value
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+type labelProcessor struct {
+	name  string
+	label string
+	drop  bool
+}
+
+func (p *labelProcessor) Name() string {
+	return p.name
+}
+
+func (p *labelProcessor) Process(ctx *ProcessorContext, event *dto.CreateEventInput) *dto.CreateEventInput {
+	if p.drop {
+		return nil
+	}
+	event.EventLabel += p.label
+	return event
+}
+
+func TestProcessorChain(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        []string
+		expectedResult string
+		expectedDrop   bool
+	}{
+		{
+			name:           "Should run enabled processors in registration order",
+			enabled:        []string{"second", "first"},
+			expectedResult: "ab",
+		},
+		{
+			name:           "Should skip processors not enabled by the project",
+			enabled:        []string{"second"},
+			expectedResult: "b",
+		},
+		{
+			name:         "Should stop the chain when a processor drops the event",
+			enabled:      []string{"first", "drop", "second"},
+			expectedDrop: true,
+		},
+		{
+			name:           "Should keep the event unchanged when no processor is enabled",
+			enabled:        []string{},
+			expectedResult: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := InitProcessorChain(
+				&labelProcessor{name: "first", label: "a"},
+				&labelProcessor{name: "drop", drop: true},
+				&labelProcessor{name: "second", label: "b"},
+			)
+
+			ctx := &ProcessorContext{Settings: &gen.FindProjectSettingsRow{Processors: test.enabled}}
+			result := chain.Run(ctx, &dto.CreateEventInput{})
+
+			if test.expectedDrop {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, test.expectedResult, result.EventLabel)
+		})
+	}
+}
+
+func TestProcessorChainRegister(t *testing.T) {
+	chain := InitProcessorChain(&labelProcessor{name: "first"})
+
+	assert.Error(t, chain.Register(&labelProcessor{name: "first"}))
+	assert.NoError(t, chain.Register(&labelProcessor{name: "second"}))
+	assert.Equal(t, []string{"first", "second"}, chain.Names())
+}
+
+func TestSampleRate(t *testing.T) {
+	rules := []string{"scroll=0.1", "*=0.5", "purchase=1"}
+
+	tests := []struct {
+		name           string
+		eventType      string
+		rules          []string
+		expectedResult float64
+	}{
+		{
+			name:           "Should use the rule of the event type",
+			eventType:      "scroll",
+			rules:          rules,
+			expectedResult: 0.1,
+		},
+		{
+			name:           "Should prefer the event type over the wildcard",
+			eventType:      "purchase",
+			rules:          rules,
+			expectedResult: 1,
+		},
+		{
+			name:           "Should use the wildcard for other types",
+			eventType:      "click",
+			rules:          rules,
+			expectedResult: 0.5,
+		},
+		{
+			name:           "Should keep all events without rules",
+			eventType:      "click",
+			rules:          []string{"scroll=0.1"},
+			expectedResult: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedResult, sampleRate(test.rules, test.eventType))
+		})
+	}
+}
+
+func TestSamplingProcessor(t *testing.T) {
+	processor := &SamplingProcessor{}
+	ctx := &ProcessorContext{Settings: &gen.FindProjectSettingsRow{SampleRules: []string{"scroll=0.1"}}}
+
+	kept := 0
+	for i := 0; i < 1000; i++ {
+		sessionID := fmt.Sprintf("session-%d", i)
+
+		scroll := processor.Process(ctx, &dto.CreateEventInput{EventType: "scroll", SessionID: sessionID})
+		again := processor.Process(ctx, &dto.CreateEventInput{EventType: "scroll", SessionID: sessionID})
+		assert.Equal(t, scroll == nil, again == nil, "sampling should be deterministic by session")
+
+		if scroll != nil {
+			assert.Equal(t, 0.1, scroll.SampleRate)
+			kept++
+		}
+
+		// a rate set before the processor is overwritten, events that are not sampled count once
+		click := processor.Process(ctx, &dto.CreateEventInput{EventType: "click", SessionID: sessionID, SampleRate: 0.0001})
+		assert.NotNil(t, click)
+		assert.Equal(t, 1.0, click.SampleRate)
+	}
+
+	assert.InDelta(t, 100, kept, 40)
+}
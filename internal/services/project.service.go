@@ -2,10 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/entities"
 	"github.com/hubkudev/sentinel/internal/repositories"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -15,6 +23,15 @@ type ProjectService interface {
 	UpdateProject(ctx context.Context, name string, desc string, url string, projectID uuid.UUID, userID uuid.UUID) error
 	GetProjectByID(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*gen.FindProjectByIDRow, error)
 	GetAllProjects(ctx context.Context, userID uuid.UUID) ([]gen.FindAllProjectsRow, error)
+	GetProjectSettings(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*gen.FindProjectSettingsRow, error)
+	UpdateProjectSettings(ctx context.Context, input *gen.UpdateProjectSettingsParams) error
+	ParseAllowedOrigins(raw string) ([]string, error)
+	ParseURLRules(queryAllowlist string, pathPatterns string) (*entities.URLRules, error)
+	ParseRedactionRules(detectors []string, patterns string) ([]string, []string, error)
+	ParseProcessors(names []string) ([]string, error)
+	ParseSampleRules(raw string) ([]string, error)
+	ParseLimits(rateLimit string, rateLimitBurst string, monthlyQuota string) (*entities.ProjectLimits, error)
+	RotateSigningSecret(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) error
 	GetProjectCount(ctx context.Context, userID uuid.UUID) (int64, error)
 	DeleteProject(ctx context.Context, userID uuid.UUID, projectID uuid.UUID) error
 	CountProjectSize(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (int64, error)
@@ -95,6 +112,209 @@ func (s *ProjectServiceImpl) GetAllProjects(ctx context.Context, userID uuid.UUI
 	return s.Repo.FindAll(ctx, userID)
 }
 
+func (s *ProjectServiceImpl) GetProjectSettings(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (*gen.FindProjectSettingsRow, error) {
+	input := gen.FindProjectSettingsParams{
+		ID:     projectID,
+		UserID: userID,
+	}
+
+	row, err := s.Repo.FindSettings(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (s *ProjectServiceImpl) UpdateProjectSettings(ctx context.Context, input *gen.UpdateProjectSettingsParams) error {
+	return s.Repo.UpdateSettings(ctx, input)
+}
+
+// ParseAllowedOrigins splits a newline or comma separated list of origins into unique hostnames.
+func (s *ProjectServiceImpl) ParseAllowedOrigins(raw string) ([]string, error) {
+	fields := splitSettingList(raw)
+
+	origins := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+
+	for _, field := range fields {
+		origin := s.UtilService.NormalizeOrigin(field)
+		if origin == "" {
+			return nil, errors.New("Invalid origin: " + field)
+		}
+		if seen[origin] {
+			continue
+		}
+		seen[origin] = true
+		origins = append(origins, origin)
+	}
+
+	if len(origins) > constants.MAX_ALLOWED_ORIGINS {
+		return nil, fmt.Errorf("Maximum of %d allowed origins", constants.MAX_ALLOWED_ORIGINS)
+	}
+
+	return origins, nil
+}
+
+// ParseURLRules parses the newline or comma separated query allowlist and path templates.
+func (s *ProjectServiceImpl) ParseURLRules(queryAllowlist string, pathPatterns string) (*entities.URLRules, error) {
+	rules := entities.URLRules{
+		QueryAllowlist: splitSettingList(queryAllowlist),
+		PathPatterns:   splitSettingList(pathPatterns),
+	}
+
+	if len(rules.QueryAllowlist) > constants.MAX_URL_RULES || len(rules.PathPatterns) > constants.MAX_URL_RULES {
+		return nil, fmt.Errorf("Maximum of %d query parameters and path templates", constants.MAX_URL_RULES)
+	}
+
+	for _, pattern := range rules.PathPatterns {
+		if !strings.HasPrefix(pattern, "/") {
+			return nil, errors.New("Path template must start with /: " + pattern)
+		}
+	}
+
+	return &rules, nil
+}
+
+// ParseRedactionRules checks the enabled built-in detectors and the newline separated custom patterns.
+// Patterns are split by newlines only since regular expressions may contain commas and spaces.
+func (s *ProjectServiceImpl) ParseRedactionRules(detectors []string, patterns string) ([]string, []string, error) {
+	enabled := make([]string, 0, len(detectors))
+	for _, detector := range detectors {
+		if !slices.Contains(entities.RedactDetectors, detector) {
+			return nil, nil, errors.New("Invalid redaction detector: " + detector)
+		}
+		if !slices.Contains(enabled, detector) {
+			enabled = append(enabled, detector)
+		}
+	}
+
+	custom := make([]string, 0)
+	for _, line := range strings.Split(patterns, "\n") {
+		pattern := strings.TrimSpace(line)
+		if pattern == "" {
+			continue
+		}
+		if len(pattern) > constants.MAX_REDACT_PATTERN_LENGTH {
+			return nil, nil, fmt.Errorf("Maximum length of a redaction pattern is %d characters", constants.MAX_REDACT_PATTERN_LENGTH)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, nil, errors.New("Invalid redaction pattern: " + pattern)
+		}
+		// a pattern matching the empty string would mask between every character
+		if re.MatchString("") {
+			return nil, nil, errors.New("Redaction pattern must not match an empty value: " + pattern)
+		}
+		custom = append(custom, pattern)
+	}
+
+	if len(custom) > constants.MAX_REDACT_PATTERNS {
+		return nil, nil, fmt.Errorf("Maximum of %d redaction patterns", constants.MAX_REDACT_PATTERNS)
+	}
+
+	return enabled, custom, nil
+}
+
+// ParseProcessors checks the enabled processors against the registered ones.
+func (s *ProjectServiceImpl) ParseProcessors(names []string) ([]string, error) {
+	registered := s.EventService.ProcessorNames()
+
+	enabled := make([]string, 0, len(names))
+	for _, name := range names {
+		if !slices.Contains(registered, name) {
+			return nil, errors.New("Invalid processor: " + name)
+		}
+		if !slices.Contains(enabled, name) {
+			enabled = append(enabled, name)
+		}
+	}
+
+	return enabled, nil
+}
+
+// ParseSampleRules parses the newline or comma separated rules such as scroll=0.1,
+// the rate is the share of the events of the type that is kept, * applies to the other types.
+func (s *ProjectServiceImpl) ParseSampleRules(raw string) ([]string, error) {
+	rules := make([]string, 0)
+	var types []string
+	for _, rule := range splitSettingList(raw) {
+		eventType, value, ok := strings.Cut(rule, "=")
+		if !ok || eventType == "" {
+			return nil, errors.New("Sample rule must be written as event_type=rate: " + rule)
+		}
+
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, errors.New("Sample rate must be between 0 and 1: " + rule)
+		}
+
+		if slices.Contains(types, eventType) {
+			return nil, errors.New("Duplicate sample rule for " + eventType)
+		}
+		types = append(types, eventType)
+		rules = append(rules, eventType+"="+strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+
+	if len(rules) > constants.MAX_SAMPLE_RULES {
+		return nil, fmt.Errorf("Maximum of %d sample rules", constants.MAX_SAMPLE_RULES)
+	}
+
+	return rules, nil
+}
+
+// ParseLimits parses the rate limit, burst and monthly quota, empty values are parsed as 0.
+func (s *ProjectServiceImpl) ParseLimits(rateLimit string, rateLimitBurst string, monthlyQuota string) (*entities.ProjectLimits, error) {
+	parse := func(value string) (int64, error) {
+		if value == "" {
+			return 0, nil
+		}
+		return strconv.ParseInt(value, 10, 64)
+	}
+
+	rate, err := parse(rateLimit)
+	if err != nil || rate < 0 || rate > int64(constants.MAX_PROJECT_RATE_LIMIT) {
+		return nil, fmt.Errorf("Rate limit must be between 0 and %d events per second", constants.MAX_PROJECT_RATE_LIMIT)
+	}
+
+	burst, err := parse(rateLimitBurst)
+	if err != nil || burst < 0 || burst > int64(constants.MAX_PROJECT_RATE_BURST) {
+		return nil, fmt.Errorf("Burst must be between 0 and %d events", constants.MAX_PROJECT_RATE_BURST)
+	}
+	if burst > 0 && burst < rate {
+		return nil, errors.New("Burst can not be lower than the rate limit")
+	}
+
+	quota, err := parse(monthlyQuota)
+	if err != nil || quota < 0 {
+		return nil, errors.New("Monthly quota must be a positive number")
+	}
+
+	return &entities.ProjectLimits{
+		RateLimit:      int32(rate),
+		RateLimitBurst: int32(burst),
+		MonthlyQuota:   quota,
+	}, nil
+}
+
+// RotateSigningSecret replaces the secret used to sign server-to-server events,
+// requests signed with the previous secret are rejected right away.
+func (s *ProjectServiceImpl) RotateSigningSecret(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) error {
+	secret := fmt.Sprintf("snt_sig_%s", s.UtilService.GenerateRandomID(48))
+	return s.Repo.UpdateSigningSecret(ctx, &gen.UpdateProjectSigningSecretParams{
+		ID:            projectID,
+		UserID:        userID,
+		SigningSecret: pgtype.Text{String: secret, Valid: true},
+	})
+}
+
+// splitSettingList splits a textarea setting by newlines, commas or spaces.
+func splitSettingList(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ',' || r == ' '
+	})
+}
+
 func (s *ProjectServiceImpl) GetProjectCount(ctx context.Context, userID uuid.UUID) (int64, error) {
 	return s.Repo.Count(ctx, userID)
 }
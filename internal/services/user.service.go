@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	gen "github.com/hubkudev/sentinel/gen"
+	"github.com/hubkudev/sentinel/internal/constants"
 	"github.com/hubkudev/sentinel/internal/repositories"
 )
 
@@ -63,12 +66,37 @@ func (s *UserServiceImpl) FindByPublicKey(key string) (*gen.FindUserByPublicKeyR
 	return &result, nil
 }
 
+// FindByPrivateKey finds the keys sharing the prefix of the token, then compares the token
+// with their hashes. Expired and revoked keys are not found.
 func (s *UserServiceImpl) FindByPrivateKey(key string) (*gen.FindUserByPrivateKeyRow, error) {
-	result, err := s.Repo.FindUserByPrivateKey(context.Background(), key)
+	if len(key) <= constants.API_KEY_PREFIX_LENGTH {
+		return nil, errors.New("invalid private key")
+	}
+
+	candidates, err := s.Repo.FindUserByPrivateKey(context.Background(), key[:constants.API_KEY_PREFIX_LENGTH])
 	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+
+	now := time.Now()
+	for _, candidate := range candidates {
+		if !keyActive(&candidate, now) {
+			continue
+		}
+		if s.UtilService.CompareToken(key, candidate.TokenSalt, candidate.TokenHash) {
+			return &candidate, nil
+		}
+	}
+
+	return nil, errors.New("invalid private key")
+}
+
+// keyActive reports whether the key is neither revoked nor expired at the given time.
+func keyActive(key *gen.FindUserByPrivateKeyRow, now time.Time) bool {
+	if key.RevokedAt.Valid {
+		return false
+	}
+	return !key.ExpiredAt.Valid || key.ExpiredAt.Time.After(now)
 }
 
 func (s *UserServiceImpl) GetPublicKey(userID uuid.UUID) (string, error) {
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/hubkudev/sentinel/gen"
 	"github.com/hubkudev/sentinel/internal/mocks"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -256,6 +258,98 @@ func TestFindByPublicKey(t *testing.T) {
 	}
 }
 
+func TestFindByPrivateKey(t *testing.T) {
+	token := "snt_AbCd1234" + faker.Password()
+	utilService := UtilServiceImpl{}
+	user := gen.FindUserByPrivateKeyRow{
+		ID:        uuid.MustParse(faker.UUIDHyphenated()),
+		Fullname:  faker.Name(),
+		Email:     faker.Email(),
+		KeyID:     1,
+		TokenSalt: "salt",
+		TokenHash: utilService.HashToken(token, "salt"),
+	}
+	expired := user
+	expired.ExpiredAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+	notExpired := user
+	notExpired.ExpiredAt = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	revoked := user
+	revoked.RevokedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+	otherKey := gen.FindUserByPrivateKeyRow{
+		KeyID:     2,
+		TokenSalt: "other-salt",
+		TokenHash: utilService.HashToken("snt_AbCd1234other", "other-salt"),
+	}
+
+	tests := []struct {
+		name           string
+		key            string
+		candidates     []gen.FindUserByPrivateKeyRow
+		expectedResult *gen.FindUserByPrivateKeyRow
+	}{
+		{
+			name:           "Should return the user of the key matching the hash",
+			key:            token,
+			candidates:     []gen.FindUserByPrivateKeyRow{otherKey, user},
+			expectedResult: &user,
+		},
+		{
+			name:       "Should return error if no key with the prefix matches the hash",
+			key:        "snt_AbCd1234wrong",
+			candidates: []gen.FindUserByPrivateKeyRow{otherKey, user},
+		},
+		{
+			name:           "Should return the user of a key expiring later",
+			key:            token,
+			candidates:     []gen.FindUserByPrivateKeyRow{notExpired},
+			expectedResult: &notExpired,
+		},
+		{
+			name:       "Should return error if the key matching the hash is expired",
+			key:        token,
+			candidates: []gen.FindUserByPrivateKeyRow{otherKey, expired},
+		},
+		{
+			name:       "Should return error if the key matching the hash is revoked",
+			key:        token,
+			candidates: []gen.FindUserByPrivateKeyRow{otherKey, revoked},
+		},
+		{
+			name:       "Should return error if no key has the prefix",
+			key:        token,
+			candidates: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUserRepo, userService := initTest(t)
+
+			// mock repo call
+			mockUserRepo.Mock.On("FindUserByPrivateKey", context.Background(), "snt_AbCd1234").Return(test.candidates, nil)
+
+			result, err := userService.FindByPrivateKey(test.key)
+
+			if test.expectedResult == nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expectedResult, result)
+			}
+		})
+	}
+
+	t.Run("Should return error without a lookup if the key is too short", func(t *testing.T) {
+		_, userService := initTest(t)
+
+		result, err := userService.FindByPrivateKey("snt_")
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
+
 func TestGetPublicKey(t *testing.T) {
 	tests := []struct {
 		name           string
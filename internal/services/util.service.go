@@ -1,12 +1,23 @@
 package services
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net"
 	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/hubkudev/sentinel/internal/constants"
+	"github.com/hubkudev/sentinel/internal/entities"
 	"github.com/hubkudev/sentinel/internal/repositories"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/oschwald/geoip2-golang"
@@ -18,9 +29,22 @@ type UtilService interface {
 	ValidateInput(payload any) string
 	GenerateHash(password string) string
 	CompareHash(password string, hash string) bool
+	HashToken(token string, salt string) string
+	CompareToken(token string, salt string, hash string) bool
 	ParseIP(str string) *netip.Addr
 	ParseTimestamp(str string) time.Time
+	CorrectTimestamp(firedAt time.Time, sentAt time.Time, receivedAt time.Time) time.Time
+	EncodeCursor(timestamp time.Time, id uuid.UUID) string
+	DecodeCursor(cursor string) (time.Time, uuid.UUID, error)
 	LookupIP(ipStr string) *geoip2.City
+	ParseUserAgent(ua string) entities.UserAgent
+	IsBot(ua string, ipStr string) bool
+	ParseAttribution(pageURL string, referrer string) entities.Attribution
+	NormalizeURL(rawURL string, rules entities.URLRules) string
+	Redact(value string, rules entities.RedactionRules) (string, int)
+	AnonymizeIP(ipStr string) string
+	NormalizeOrigin(origin string) string
+	IsOriginAllowed(origin string, allowed []string) bool
 	LookupAlphabetFromIdx(index int) string
 	ByteToJSON(v []byte) interface{}
 }
@@ -134,6 +158,17 @@ func (s *UtilServiceImpl) CompareHash(password string, hash string) bool {
 	return true
 }
 
+// HashToken hashes random tokens such as API keys. Unlike passwords they are long enough
+// for a single sha256, which keeps the lookup on every API request cheap.
+func (s *UtilServiceImpl) HashToken(token string, salt string) string {
+	sum := sha256.Sum256([]byte(salt + token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *UtilServiceImpl) CompareToken(token string, salt string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(s.HashToken(token, salt)), []byte(hash)) == 1
+}
+
 func (s *UtilServiceImpl) ParseIP(str string) *netip.Addr {
 	if str == "" {
 		return nil
@@ -150,6 +185,47 @@ func (s *UtilServiceImpl) ParseTimestamp(str string) time.Time {
 	return parsedTime
 }
 
+// CorrectTimestamp moves firedAt by the offset between the client's clock, known from sentAt,
+// and the server's clock when the request was received. firedAt is returned as is without sentAt.
+func (s *UtilServiceImpl) CorrectTimestamp(firedAt time.Time, sentAt time.Time, receivedAt time.Time) time.Time {
+	if sentAt.IsZero() {
+		return firedAt
+	}
+	return firedAt.Add(receivedAt.Sub(sentAt))
+}
+
+// EncodeCursor encodes the position of a row ordered by timestamp and id as an opaque cursor.
+func (s *UtilServiceImpl) EncodeCursor(timestamp time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(timestamp.Format(time.RFC3339Nano) + "/" + id.String()))
+}
+
+// DecodeCursor returns the timestamp and id of a cursor made by EncodeCursor.
+func (s *UtilServiceImpl) DecodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	invalid := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	timestamp, id, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	return parsedTime, parsedID, nil
+}
+
 func (s *UtilServiceImpl) LookupIP(ipStr string) *geoip2.City {
 	ip := net.ParseIP(ipStr)
 	if ip == nil {
@@ -180,3 +256,437 @@ func (s *UtilServiceImpl) ByteToJSON(v []byte) interface{} {
 	}
 	return result
 }
+
+// uaBrowsers is ordered by precedence, since most browsers also
+// mention the engine they are based on, e.g. Edge UA contains "Chrome/" and "Safari/".
+var uaBrowsers = []struct {
+	Name   string
+	Tokens []string
+}{
+	{Name: "Edge", Tokens: []string{"Edg/", "EdgA/", "EdgiOS/", "Edge/"}},
+	{Name: "Opera", Tokens: []string{"OPR/", "OPiOS/", "Opera/"}},
+	{Name: "Samsung Internet", Tokens: []string{"SamsungBrowser/"}},
+	{Name: "Firefox", Tokens: []string{"Firefox/", "FxiOS/"}},
+	{Name: "Chrome", Tokens: []string{"CriOS/", "Chrome/"}},
+	{Name: "Safari", Tokens: []string{"Version/"}},
+	{Name: "Internet Explorer", Tokens: []string{"MSIE ", "rv:"}},
+}
+
+var uaWindowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// ParseUserAgent derives browser, OS and device class from the User-Agent string.
+// Unknown parts are left empty.
+func (s *UtilServiceImpl) ParseUserAgent(ua string) entities.UserAgent {
+	var result entities.UserAgent
+	if ua == "" {
+		return result
+	}
+
+	for _, browser := range uaBrowsers {
+		// Safari and IE tokens are too generic, only trust them with their engine marker
+		if browser.Name == "Safari" && !strings.Contains(ua, "Safari/") {
+			continue
+		}
+		if browser.Name == "Internet Explorer" && !strings.Contains(ua, "MSIE ") && !strings.Contains(ua, "Trident/") {
+			continue
+		}
+
+		if version, ok := uaVersionAfter(ua, browser.Tokens...); ok {
+			result.BrowserName = browser.Name
+			result.BrowserVersion = version
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "Windows NT "):
+		version, _ := uaVersionAfter(ua, "Windows NT ")
+		result.OSName = "Windows"
+		result.OSVersion = uaWindowsVersions[version]
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPad") || strings.Contains(ua, "iPod"):
+		version, _ := uaVersionAfter(ua, "iPhone OS ", "CPU OS ")
+		result.OSName = "iOS"
+		result.OSVersion = version
+	case strings.Contains(ua, "Android"):
+		version, _ := uaVersionAfter(ua, "Android ")
+		result.OSName = "Android"
+		result.OSVersion = version
+	case strings.Contains(ua, "Mac OS X"):
+		version, _ := uaVersionAfter(ua, "Mac OS X ")
+		result.OSName = "macOS"
+		result.OSVersion = version
+	case strings.Contains(ua, "CrOS"):
+		result.OSName = "Chrome OS"
+	case strings.Contains(ua, "Linux"):
+		result.OSName = "Linux"
+	}
+
+	switch {
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") ||
+		(result.OSName == "Android" && !strings.Contains(ua, "Mobile")):
+		result.DeviceType = entities.DeviceTablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		result.DeviceType = entities.DeviceMobile
+	case result.OSName != "":
+		result.DeviceType = entities.DeviceDesktop
+	}
+
+	return result
+}
+
+// uaVersionAfter returns the version number following the first matching token,
+// underscores are normalized into dots (iOS and macOS use "17_4" instead of "17.4").
+func uaVersionAfter(ua string, tokens ...string) (string, bool) {
+	for _, token := range tokens {
+		idx := strings.Index(ua, token)
+		if idx == -1 {
+			continue
+		}
+
+		rest := ua[idx+len(token):]
+		end := 0
+		for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || rest[end] == '.' || rest[end] == '_') {
+			end++
+		}
+
+		return strings.ReplaceAll(rest[:end], "_", "."), true
+	}
+
+	return "", false
+}
+
+// botIPRanges is parsed once from constants.BOT_IP_RANGES.
+var botIPRanges = func() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(constants.BOT_IP_RANGES))
+	for _, r := range constants.BOT_IP_RANGES {
+		prefixes = append(prefixes, netip.MustParsePrefix(r))
+	}
+	return prefixes
+}()
+
+// IsBot detects crawler traffic from the User-Agent patterns and known crawler IP ranges.
+func (s *UtilServiceImpl) IsBot(ua string, ipStr string) bool {
+	lowerUA := strings.ToLower(ua)
+	for _, exception := range constants.BOT_USER_AGENT_EXCEPTIONS {
+		lowerUA = strings.ReplaceAll(lowerUA, exception, "")
+	}
+
+	// a User-Agent may be the crawler name alone, e.g. Facebot
+	if strings.HasSuffix(lowerUA, "bot") {
+		return true
+	}
+
+	for _, pattern := range constants.BOT_USER_AGENT_PATTERNS {
+		if strings.Contains(lowerUA, pattern) {
+			return true
+		}
+	}
+
+	ip := s.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+
+	for _, prefix := range botIPRanges {
+		if prefix.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AnonymizeIP zeroes the host part of the IP, keeping the first 24 bits of IPv4 and 48 bits of IPv6.
+func (s *UtilServiceImpl) AnonymizeIP(ipStr string) string {
+	ip := s.ParseIP(ipStr)
+	if ip == nil {
+		return ""
+	}
+
+	addr := ip.Unmap()
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	return prefix.Addr().String()
+}
+
+// NormalizeOrigin reduces an origin, URL or domain into a lowercase hostname.
+// wildcard entries such as "*" and "*.example.com" are kept as is.
+func (s *UtilServiceImpl) NormalizeOrigin(origin string) string {
+	origin = strings.ToLower(strings.TrimSpace(origin))
+	if origin == "" || origin == "*" {
+		return origin
+	}
+
+	if !strings.Contains(origin, "://") {
+		origin = "https://" + origin
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
+
+// IsOriginAllowed checks the origin against the allowed hostnames,
+// "*.example.com" matches any subdomain of example.com and "*" matches everything.
+func (s *UtilServiceImpl) IsOriginAllowed(origin string, allowed []string) bool {
+	host := s.NormalizeOrigin(origin)
+	if host == "" {
+		return false
+	}
+
+	for _, entry := range allowed {
+		pattern := s.NormalizeOrigin(entry)
+
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "*":
+			return true
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		case host == pattern:
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseAttribution extracts the UTM parameters from the page URL and the referrer domain,
+// then classifies the visit into a channel. Referrers from the page's own host are internal
+// navigation and are not counted as a referrer.
+func (s *UtilServiceImpl) ParseAttribution(pageURL string, referrer string) entities.Attribution {
+	var attr entities.Attribution
+
+	var pageHost string
+	if page, err := url.Parse(pageURL); err == nil {
+		pageHost = strings.ToLower(page.Hostname())
+		query := page.Query()
+		attr.UTMSource = query.Get("utm_source")
+		attr.UTMMedium = query.Get("utm_medium")
+		attr.UTMCampaign = query.Get("utm_campaign")
+		attr.UTMTerm = query.Get("utm_term")
+		attr.UTMContent = query.Get("utm_content")
+	}
+
+	if ref, err := url.Parse(referrer); err == nil {
+		host := strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+		if host != "" && host != strings.TrimPrefix(pageHost, "www.") {
+			attr.ReferrerDomain = host
+		}
+	}
+
+	attr.Channel = classifyChannel(&attr)
+	return attr
+}
+
+func classifyChannel(attr *entities.Attribution) string {
+	if channel, ok := constants.UTM_MEDIUM_CHANNELS[strings.ToLower(attr.UTMMedium)]; ok {
+		return channel
+	}
+
+	// utm_source is usually the domain name without tld, e.g. "google" or "facebook"
+	for _, source := range []string{attr.ReferrerDomain, strings.ToLower(attr.UTMSource)} {
+		if source == "" {
+			continue
+		}
+		switch {
+		case matchDomains(source, constants.EMAIL_DOMAINS):
+			return entities.ChannelEmail
+		case matchDomains(source, constants.SEARCH_ENGINE_DOMAINS):
+			return entities.ChannelSearch
+		case matchDomains(source, constants.SOCIAL_DOMAINS):
+			return entities.ChannelSocial
+		}
+	}
+
+	switch {
+	case attr.ReferrerDomain != "":
+		return entities.ChannelReferral
+	case attr.UTMSource != "" || attr.UTMCampaign != "":
+		return entities.ChannelCampaign
+	default:
+		return entities.ChannelDirect
+	}
+}
+
+// matchDomains checks the host against the domain list, subdomains of a listed domain are matched as well.
+// bare utm_source values such as "google" or "facebook" are compared with the domain's first label,
+// single letters are not so "t" or "x" are not taken for t.co or x.com.
+func matchDomains(host string, domains []string) bool {
+	for _, domain := range domains {
+		switch {
+		case !strings.Contains(host, "."):
+			if len(host) > 1 && host == strings.Split(domain, ".")[0] {
+				return true
+			}
+		case strings.HasSuffix(domain, "."):
+			if strings.HasPrefix(host, domain) || strings.Contains(host, "."+domain) {
+				return true
+			}
+		case host == domain || strings.HasSuffix(host, "."+domain):
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeURL groups the page URL into a page path: the host is lowercased, the fragment,
+// trailing slash and query parameters outside the allowlist are stripped, then the path is
+// replaced by the first matching path template. The scheme is dropped, e.g.
+// "https://Example.com/product/123/?ref=x#top" becomes "example.com/product/:id".
+func (s *UtilServiceImpl) NormalizeURL(rawURL string, rules entities.URLRules) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	path := parsed.EscapedPath()
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	for _, pattern := range rules.PathPatterns {
+		if matchPathPattern(path, pattern) {
+			path = pattern
+			break
+		}
+	}
+
+	// only allowlisted query parameters are kept, encoded in a stable order
+	query := url.Values{}
+	for _, key := range rules.QueryAllowlist {
+		if values, ok := parsed.Query()[key]; ok {
+			query[key] = values
+		}
+	}
+
+	normalized := strings.ToLower(parsed.Host) + path
+	if len(query) > 0 {
+		normalized += "?" + query.Encode()
+	}
+
+	return normalized
+}
+
+// matchPathPattern matches the path against a template, ":name" matches any single
+// segment and a trailing "*" matches the remaining segments.
+func matchPathPattern(path string, pattern string) bool {
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+
+	for i, segment := range patternSegments {
+		if segment == "*" && i == len(patternSegments)-1 {
+			return len(pathSegments) >= i
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return len(pathSegments) == len(patternSegments)
+}
+
+// redactPatterns are the built-in detectors, in the order they are applied.
+// Emails may be URL encoded in query strings, credit card matches are checked with luhn.
+var redactPatterns = []struct {
+	detector string
+	pattern  *regexp.Regexp
+}{
+	{entities.RedactJWT, regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)},
+	{entities.RedactEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+(?:@|%40)[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)},
+	{entities.RedactCreditCard, regexp.MustCompile(`\b[2-6]\d(?:[ -]?\d){11,17}\b`)},
+	{entities.RedactPhone, regexp.MustCompile(`\+\d{1,3}(?:[ .-]?\(?\d{2,4}\)?){2,5}|\(?\b\d{3}\)?[ .-]\d{3}[ .-]\d{4}\b`)},
+}
+
+// Redact masks the matches of the enabled built-in detectors and the custom patterns,
+// returns the masked value and the number of redacted matches.
+func (s *UtilServiceImpl) Redact(value string, rules entities.RedactionRules) (string, int) {
+	if value == "" {
+		return value, 0
+	}
+
+	count := 0
+	mask := func(pattern *regexp.Regexp, valid func(string) bool) {
+		value = pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if valid != nil && !valid(match) {
+				return match
+			}
+			count++
+			return constants.REDACTED_VALUE
+		})
+	}
+
+	for _, detector := range redactPatterns {
+		for _, enabled := range rules.Detectors {
+			if enabled != detector.detector {
+				continue
+			}
+			if detector.detector == entities.RedactCreditCard {
+				mask(detector.pattern, isLuhnValid)
+			} else {
+				mask(detector.pattern, nil)
+			}
+		}
+	}
+
+	for _, pattern := range rules.Patterns {
+		mask(pattern, nil)
+	}
+
+	return value, count
+}
+
+// isLuhnValid checks the luhn checksum of the digits in the card number.
+func isLuhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -229,7 +229,13 @@ func (s *WebServiceImpl) SendProjectsPage(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusInternalServerError)
 	}
 
-	return configs.Render(c, pages.ProjectsPage(user, projects))
+	// public key is used to render the tracker snippet of each project
+	publicKey, err := s.UserService.GetPublicKey(user.ID)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return configs.Render(c, pages.ProjectsPage(user, projects, publicKey, c.BaseURL(), s.EventService.ProcessorNames()))
 }
 
 func (s *WebServiceImpl) SendAPIKeysPage(c *fiber.Ctx) error {
@@ -245,7 +251,17 @@ func (s *WebServiceImpl) SendAPIKeysPage(c *fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusInternalServerError)
 	}
 
-	return configs.Render(c, pages.APIKeysPage(user, publicKey, privateKeys))
+	projects, err := s.ProjectService.GetAllProjects(context.Background(), user.ID)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	auditLogs, err := s.KeyService.GetAuditLogs(context.Background(), user.ID)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return configs.Render(c, pages.APIKeysPage(user, publicKey, privateKeys, projects, auditLogs))
 }
 
 func (s *WebServiceImpl) SendTOSPage(c *fiber.Ctx) error {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,8 +34,9 @@ func main() {
 	godotenv.Load()
 
 	app := fiber.New(fiber.Config{
-		Views:       engine,
-		ProxyHeader: "CF-Connecting-IP",
+		Views:        engine,
+		ProxyHeader:  "CF-Connecting-IP",
+		ErrorHandler: middlewares.APIErrorHandler,
 	})
 
 	// Encrypt Cookie Config
@@ -44,7 +46,8 @@ func main() {
 
 	// CORS Policy
 	app.Use(cors.New(cors.Config{
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-API-Key, Idempotency-Key",
+		ExposeHeaders: "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset",
 	}))
 
 	// Logging
@@ -121,10 +124,49 @@ func main() {
 	projectRepo := repositories.InitProjectRepo(repository, db)
 	userRepo := repositories.InitUserRepo(repository)
 	downloadRepo := repositories.InitDownloadRepo(repository)
-	keyRepo := repositories.InitKeyRepo(repository)
+	keyRepo := repositories.InitKeyRepo(repository, db)
 	aggrRepo := repositories.InitAggrRepo(repository)
 	ipRepo := repositories.InitIPDBRepo(ipdbCon)
 
+	// window for deduplicating client supplied event ids, e.g. EVENT_DEDUP_WINDOW=12h
+	if window, err := time.ParseDuration(os.Getenv("EVENT_DEDUP_WINDOW")); err == nil {
+		constants.EVENT_DEDUP_WINDOW = window
+	}
+
+	// accepted window of the events' FiredAt, e.g. EVENT_MAX_AGE=168h EVENT_MAX_FUTURE=1m
+	if age, err := time.ParseDuration(os.Getenv("EVENT_MAX_AGE")); err == nil {
+		constants.EVENT_MAX_AGE = age
+	}
+	if future, err := time.ParseDuration(os.Getenv("EVENT_MAX_FUTURE")); err == nil {
+		constants.EVENT_MAX_FUTURE = future
+	}
+
+	// default token bucket of each public key, e.g. PUBLIC_KEY_RATE_LIMIT=200 PUBLIC_KEY_RATE_BURST=500
+	if rate, err := strconv.Atoi(os.Getenv("PUBLIC_KEY_RATE_LIMIT")); err == nil && rate > 0 {
+		constants.PUBLIC_KEY_RATE_LIMIT = rate
+	}
+	if burst, err := strconv.Atoi(os.Getenv("PUBLIC_KEY_RATE_BURST")); err == nil && burst > 0 {
+		constants.PUBLIC_KEY_RATE_BURST = burst
+	}
+
+	// default token bucket of projects without their own limits, e.g. PROJECT_RATE_LIMIT=50 PROJECT_RATE_BURST=100
+	if rate, err := strconv.Atoi(os.Getenv("PROJECT_RATE_LIMIT")); err == nil && rate >= 0 {
+		constants.PROJECT_RATE_LIMIT = rate
+	}
+	if burst, err := strconv.Atoi(os.Getenv("PROJECT_RATE_BURST")); err == nil && burst >= 0 {
+		constants.PROJECT_RATE_BURST = burst
+	}
+
+	// events stored per user each month, e.g. MONTHLY_EVENT_QUOTA=1000000, unlimited if unset or 0
+	if quota, err := strconv.ParseInt(os.Getenv("MONTHLY_EVENT_QUOTA"), 10, 64); err == nil && quota >= 0 {
+		constants.MONTHLY_EVENT_QUOTA = quota
+	}
+
+	// default time a rotated API key keeps working, e.g. API_KEY_ROTATION_GRACE=48h
+	if grace, err := time.ParseDuration(os.Getenv("API_KEY_ROTATION_GRACE")); err == nil && grace >= 0 && grace <= constants.MAX_API_KEY_ROTATION_GRACE {
+		constants.API_KEY_ROTATION_GRACE = grace
+	}
+
 	// init worker pool
 	workerPool := services.InitWorkerPool(constants.WORKER_POOL_COUNT, constants.WORKER_BUFFER_SIZE)
 	workerPool.StartWorker(context.Background())
@@ -136,7 +178,12 @@ func main() {
 	userService := services.InitUserService(&utilService, &userRepo)
 	authService := services.InitAuthService(&utilService, &userService, sessionStore)
 	aggrService := services.InitAggrService(&utilService, &aggrRepo, &projectRepo)
-	eventService := services.InitEventService(&utilService, &cacheService, &aggrService, *workerPool, &eventRepo, &projectRepo)
+
+	// event processors run on ingestion in this order, enabled per project by name.
+	// internal enrichers can be added with processors.Register.
+	processors := services.InitProcessorChain(services.BuiltinProcessors(&utilService)...)
+
+	eventService := services.InitEventService(&utilService, &cacheService, &aggrService, *workerPool, processors, &eventRepo, &projectRepo)
 	projectService := services.InitProjectService(&projectRepo, &eventService, &utilService)
 	keyService := services.InitKeyService(&utilService, &keyRepo)
 	apiService := services.InitAPIService(
@@ -156,7 +203,7 @@ func main() {
 	)
 
 	// init middleware
-	m := middlewares.InitMiddleware(&userService, sessionStore, &cacheService)
+	m := middlewares.InitMiddleware(&userService, sessionStore, &cacheService, &keyService)
 
 	// init routes
 	routes.InitAuthRoute(app, &authService)
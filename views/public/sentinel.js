@@ -0,0 +1 @@
+(()=>{var p="sentinel_session",A="sentinel_anonymous_id",D="sentinel_distinct_id",h=30*60*1e3,m=5,S=100,E=255,n=document.currentScript,u=n?.dataset.key??"",l=n?.dataset.project??"",g=n?.dataset.api??(n?new URL(n.src).origin:""),a=`${g}/api/v1/event`,I=`${g}/api/v1/identify`,i=Date.now(),V=0,z=!1,s=location.href;function c(){return window.crypto?.randomUUID?window.crypto.randomUUID():"xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx".replace(/[xy]/g,e=>{let t=Math.random()*16|0;return(e==="x"?t:t&3|8).toString(16)})}var v=c();function G(e){try{return localStorage.getItem(e)}catch{return null}}function k(e,t){try{t===null?localStorage.removeItem(e):localStorage.setItem(e,t)}catch{}}var N=G(A)??c(),R=G(D);k(A,N);function y(){let e=Date.now();try{let t=JSON.parse(localStorage.getItem(p)??"null");if(t&&e-t.lastSeen<h)return t.lastSeen=e,localStorage.setItem(p,JSON.stringify(t)),t.id;let o={id:c(),lastSeen:e};return localStorage.setItem(p,JSON.stringify(o)),o.id}catch{return v}}function x(e){let t=[],o=e;for(;o&&o.nodeType===Node.ELEMENT_NODE&&t.length<m;){let r=o.tagName.toLowerCase();if(o.id){t.unshift(`${r}#${o.id}`);break}o.classList.length>0&&(r+="."+Array.from(o.classList).slice(0,2).join(".")),t.unshift(r),o=o.parentElement}return t.join(" > ").slice(0,E)}function d(e,t={}){if(!u||!l)return;let o={PublicKey:u,ProjectID:l,EventID:c(),EventType:e,EventLabel:t.label?.slice(0,S),PageURL:location.href,Referrer:document.referrer||void 0,ElementPath:t.elementPath,ElementType:t.elementType,SessionID:y(),TimeOnPage:t.timeOnPage,ScreenResolution:`${screen.width}x${screen.height}`,FiredAt:new Date().toISOString(),SentAt:new Date().toISOString(),DistinctID:R??void 0,AnonymousID:N,Properties:t.properties};P(a,o)}function P(e,t){let o=JSON.stringify(t);navigator.sendBeacon&&navigator.sendBeacon(e,new Blob([o],{type:"text/plain"}))||fetch(e,{method:"POST",body:o,headers:{"Content-Type":"application/json"},keepalive:!0}).catch(()=>{})}function U(e){!u||!l||!e||(R=e,k(D,e),P(I,{PublicKey:u,ProjectID:l,AnonymousID:N,DistinctID:e}))}function O(){R=null,N=c(),k(D,null),k(A,N)}function f(){if(z)return;z=!0;let e=V;document.visibilityState==="visible"&&(e+=Date.now()-i),d("page_leave",{label:document.title,timeOnPage:e})}function b(){i=Date.now(),V=0,z=!1,s=location.href,d("page_view",{label:document.title})}function w(){location.href!==s&&(f(),b())}function T(e){let t=history[e];history[e]=function(...o){let r=t.apply(this,o);return w(),r}}function L(e){let t=e.target instanceof Element?e.target.closest("a, button, input, select, textarea, [data-sentinel-label]"):null;if(!t)return;let o=t.getAttribute("data-sentinel-label")??t.getAttribute("aria-label")??t.textContent?.trim()??"";d("click",{label:o,elementPath:x(t),elementType:t.tagName.toLowerCase()})}T("pushState");T("replaceState");window.addEventListener("popstate",w);document.addEventListener("click",L,{capture:!0});document.addEventListener("visibilitychange",()=>{document.visibilityState==="hidden"?V+=Date.now()-i:i=Date.now()});window.addEventListener("pagehide",f);window.addEventListener("pageshow",e=>{e.persisted&&b()});window.sentinel={track:d,identify:U,reset:O};b();})();
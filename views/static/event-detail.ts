@@ -356,6 +356,9 @@ function handleLiveEventSelector() {
             case "last_100":
                 liveEventTable?.setAttribute("hx-get", `/api/event/live/${id}?strategy=last_100`);
                 break;
+            case "trusted":
+                liveEventTable?.setAttribute("hx-get", `/api/event/live/${id}?strategy=trusted`);
+                break;
             default:
                 break;
         }
@@ -0,0 +1,267 @@
+// Sentinel first-party tracker.
+//
+// Usage:
+// <script defer src="https://your-sentinel-host/static/sentinel.js" data-key="PUBLIC_KEY" data-project="PROJECT_ID"></script>
+//
+// Tracks pageviews (including SPA navigation), clicks and time on page automatically.
+// Custom events can be sent with window.sentinel.track("event_type", { label, properties }).
+// Logged in users can be identified with window.sentinel.identify("user_id"), which merges
+// the events sent anonymously before. window.sentinel.reset() forgets the user on logout.
+
+interface TrackOptions {
+    label?: string;
+    elementPath?: string;
+    elementType?: string;
+    timeOnPage?: number;
+    properties?: Record<string, unknown>;
+}
+
+const SESSION_KEY = "sentinel_session";
+const ANONYMOUS_ID_KEY = "sentinel_anonymous_id";
+const DISTINCT_ID_KEY = "sentinel_distinct_id";
+const SESSION_TIMEOUT = 30 * 60 * 1000; // 30 minutes of inactivity
+const MAX_PATH_DEPTH = 5;
+const MAX_LABEL_LENGTH = 100;
+const MAX_ELEMENT_PATH_LENGTH = 255;
+
+const script = document.currentScript as HTMLScriptElement | null;
+const publicKey = script?.dataset.key ?? "";
+const projectID = script?.dataset.project ?? "";
+const apiHost = script?.dataset.api ?? (script ? new URL(script.src).origin : "");
+const endpoint = `${apiHost}/api/v1/event`;
+const identifyEndpoint = `${apiHost}/api/v1/identify`;
+
+// time on page only counts while the page is visible, page_leave is sent once per page view.
+let pageStart = Date.now();
+let visibleTime = 0;
+let leaveSent = false;
+let currentURL = location.href;
+
+function randomID(): string {
+    if (window.crypto?.randomUUID) {
+        return window.crypto.randomUUID();
+    }
+    return "xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx".replace(/[xy]/g, c => {
+        const r = Math.random() * 16 | 0;
+        return (c === "x" ? r : (r & 0x3 | 0x8)).toString(16);
+    });
+}
+
+const pageSessionID = randomID();
+
+function getStored(key: string): string | null {
+    try {
+        return localStorage.getItem(key);
+    } catch {
+        return null;
+    }
+}
+
+function setStored(key: string, value: string | null) {
+    try {
+        if (value === null) {
+            localStorage.removeItem(key);
+        } else {
+            localStorage.setItem(key, value);
+        }
+    } catch {
+        // storage can be unavailable, ids are then kept for the current page only.
+    }
+}
+
+let anonymousID = getStored(ANONYMOUS_ID_KEY) ?? randomID();
+let distinctID = getStored(DISTINCT_ID_KEY);
+setStored(ANONYMOUS_ID_KEY, anonymousID);
+
+// session id is kept in localStorage and renewed after 30 minutes of inactivity.
+function getSessionID(): string {
+    const now = Date.now();
+    try {
+        const stored = JSON.parse(localStorage.getItem(SESSION_KEY) ?? "null");
+        if (stored && now - stored.lastSeen < SESSION_TIMEOUT) {
+            stored.lastSeen = now;
+            localStorage.setItem(SESSION_KEY, JSON.stringify(stored));
+            return stored.id;
+        }
+        const session = { id: randomID(), lastSeen: now };
+        localStorage.setItem(SESSION_KEY, JSON.stringify(session));
+        return session.id;
+    } catch {
+        // storage can be unavailable (e.g. privacy mode), fallback to a per page session.
+        return pageSessionID;
+    }
+}
+
+function getElementPath(elem: Element): string {
+    const parts: string[] = [];
+    let current: Element | null = elem;
+
+    while (current && current.nodeType === Node.ELEMENT_NODE && parts.length < MAX_PATH_DEPTH) {
+        let part = current.tagName.toLowerCase();
+        if (current.id) {
+            parts.unshift(`${part}#${current.id}`);
+            break;
+        }
+        if (current.classList.length > 0) {
+            part += "." + Array.from(current.classList).slice(0, 2).join(".");
+        }
+        parts.unshift(part);
+        current = current.parentElement;
+    }
+
+    return parts.join(" > ").slice(0, MAX_ELEMENT_PATH_LENGTH);
+}
+
+function send(eventType: string, options: TrackOptions = {}) {
+    if (!publicKey || !projectID) {
+        return;
+    }
+
+    const payload = {
+        PublicKey: publicKey,
+        ProjectID: projectID,
+        EventID: randomID(),
+        EventType: eventType,
+        EventLabel: options.label?.slice(0, MAX_LABEL_LENGTH),
+        PageURL: location.href,
+        Referrer: document.referrer || undefined,
+        ElementPath: options.elementPath,
+        ElementType: options.elementType,
+        SessionID: getSessionID(),
+        TimeOnPage: options.timeOnPage,
+        ScreenResolution: `${screen.width}x${screen.height}`,
+        FiredAt: new Date().toISOString(),
+        SentAt: new Date().toISOString(),
+        DistinctID: distinctID ?? undefined,
+        AnonymousID: anonymousID,
+        Properties: options.properties,
+    };
+
+    post(endpoint, payload);
+}
+
+function post(url: string, payload: object) {
+    // text/plain does not trigger a CORS preflight, the server reads it as JSON.
+    const body = JSON.stringify(payload);
+    if (navigator.sendBeacon) {
+        const sent = navigator.sendBeacon(url, new Blob([body], { type: "text/plain" }));
+        if (sent) {
+            return;
+        }
+    }
+
+    fetch(url, {
+        method: "POST",
+        body,
+        headers: { "Content-Type": "application/json" },
+        keepalive: true,
+    }).catch(() => { });
+}
+
+function identify(id: string) {
+    if (!publicKey || !projectID || !id) {
+        return;
+    }
+
+    distinctID = id;
+    setStored(DISTINCT_ID_KEY, id);
+
+    post(identifyEndpoint, {
+        PublicKey: publicKey,
+        ProjectID: projectID,
+        AnonymousID: anonymousID,
+        DistinctID: id,
+    });
+}
+
+// reset forgets the identified user, e.g. on logout, and starts a new anonymous id.
+function reset() {
+    distinctID = null;
+    anonymousID = randomID();
+    setStored(DISTINCT_ID_KEY, null);
+    setStored(ANONYMOUS_ID_KEY, anonymousID);
+}
+
+function trackPageLeave() {
+    if (leaveSent) {
+        return;
+    }
+    leaveSent = true;
+
+    let timeOnPage = visibleTime;
+    if (document.visibilityState === "visible") {
+        timeOnPage += Date.now() - pageStart;
+    }
+    send("page_leave", { label: document.title, timeOnPage });
+}
+
+function trackPageView() {
+    pageStart = Date.now();
+    visibleTime = 0;
+    leaveSent = false;
+    currentURL = location.href;
+    send("page_view", { label: document.title });
+}
+
+// SPA navigation changes the url without reloading the page.
+function onLocationChange() {
+    if (location.href === currentURL) {
+        return;
+    }
+    trackPageLeave();
+    trackPageView();
+}
+
+function patchHistory(method: "pushState" | "replaceState") {
+    const original = history[method];
+    history[method] = function (...args: Parameters<History["pushState"]>) {
+        const result = original.apply(this, args);
+        onLocationChange();
+        return result;
+    };
+}
+
+function onClick(e: MouseEvent) {
+    const target = e.target instanceof Element ? e.target.closest("a, button, input, select, textarea, [data-sentinel-label]") : null;
+    if (!target) {
+        return;
+    }
+
+    const label = target.getAttribute("data-sentinel-label")
+        ?? target.getAttribute("aria-label")
+        ?? target.textContent?.trim()
+        ?? "";
+
+    send("click", {
+        label,
+        elementPath: getElementPath(target),
+        elementType: target.tagName.toLowerCase(),
+    });
+}
+
+patchHistory("pushState");
+patchHistory("replaceState");
+window.addEventListener("popstate", onLocationChange);
+document.addEventListener("click", onClick, { capture: true });
+document.addEventListener("visibilitychange", () => {
+    if (document.visibilityState === "hidden") {
+        visibleTime += Date.now() - pageStart;
+    } else {
+        pageStart = Date.now();
+    }
+});
+window.addEventListener("pagehide", trackPageLeave);
+// a page restored from the back/forward cache is a new page view
+window.addEventListener("pageshow", (e) => {
+    if (e.persisted) {
+        trackPageView();
+    }
+});
+
+(window as any).sentinel = {
+    track: send,
+    identify,
+    reset,
+};
+
+trackPageView();